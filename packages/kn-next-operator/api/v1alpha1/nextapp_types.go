@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,6 +25,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // NextAppSpec defines the desired state of NextApp
+// +kubebuilder:validation:XValidation:rule="!has(self.deletionPolicy) || self.deletionPolicy != 'Delete' || !has(self.storage) || !has(self.storage.provider) || self.storage.provider != 'azure'",message="deletionPolicy Delete is not supported with azure storage, which has no per-app prefix",fieldPath=".deletionPolicy"
 type NextAppSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -57,39 +59,604 @@ type NextAppSpec struct {
 	// GitOps Preview Environment configuration
 	// +optional
 	Preview *PreviewSpec `json:"preview,omitempty"`
+
+	// Readiness, liveness and startup probes for the Next.js container
+	// +optional
+	Probes *ProbesSpec `json:"probes,omitempty"`
+
+	// Prometheus scraping and Grafana dashboard provisioning
+	// +optional
+	Observability *ObservabilitySpec `json:"observability,omitempty"`
+
+	// Progressive traffic shifting to new revisions. When unset, Knative
+	// routes all traffic to the latest ready revision.
+	// +optional
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+
+	// Automatic rollback when a new revision fails to become ready
+	// +optional
+	Rollback *RollbackSpec `json:"rollback,omitempty"`
+
+	// Custom domains mapped to the app with Knative DomainMappings
+	// +listType=map
+	// +listMapKey=host
+	// +optional
+	Domains []DomainSpec `json:"domains,omitempty"`
+
+	// What happens to the app's object-storage prefix and Redis cache keys
+	// when the NextApp is deleted. Delete purges them with a Job before the
+	// NextApp is released. Azure storage has no per-app prefix, so Delete is
+	// not supported with it.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// What the operator does when an owned object was edited by hand so it no
+	// longer matches the spec. Correct reverts the edit; Report leaves it in
+	// place and raises the DriftDetected condition.
+	// +kubebuilder:validation:Enum=Correct;Report
+	// +kubebuilder:default=Correct
+	// +optional
+	DriftPolicy string `json:"driftPolicy,omitempty"`
 }
 
+// Deletion policies for the app's storage and cache entries.
+const (
+	DeletionPolicyRetain = "Retain"
+	DeletionPolicyDelete = "Delete"
+)
+
+// Drift policies for hand edits to owned objects.
+const (
+	DriftPolicyCorrect = "Correct"
+	DriftPolicyReport  = "Report"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.enabled) || !self.enabled || (has(self.prId) && size(self.prId) > 0)",message="prId is required when preview is enabled",fieldPath=".prId"
 type PreviewSpec struct {
 	Enabled bool   `json:"enabled,omitempty"`
 	Branch  string `json:"branch,omitempty"`
 	PRID    string `json:"prId,omitempty"`
 }
 
+// Scaling and cache defaults. The defaulting webhook writes them into the
+// stored spec, and the reconciler falls back to them when it runs without
+// webhooks.
+const (
+	DefaultMinScale             int32 = 0
+	DefaultMaxScale             int32 = 10
+	DefaultContainerConcurrency int32 = 100
+	DefaultBytecodeCacheSize          = "512Mi"
+)
+
+// ContainerPort is the port the Next.js server listens on.
+const ContainerPort int32 = 3000
+
+// +kubebuilder:validation:XValidation:rule="!has(self.minScale) || !has(self.maxScale) || self.maxScale == 0 || self.minScale <= self.maxScale",message="minScale must not exceed maxScale",fieldPath=".minScale"
 type ScalingSpec struct {
-	MinScale             int32 `json:"minScale,omitempty"`
-	MaxScale             int32 `json:"maxScale,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MinScale int32 `json:"minScale,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MaxScale int32 `json:"maxScale,omitempty"`
+	// +kubebuilder:validation:Minimum=0
 	ContainerConcurrency int32 `json:"containerConcurrency,omitempty"`
+
+	// CPU requested for the Next.js container
+	// +kubebuilder:default="250m"
+	// +optional
+	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+
+	// Memory requested for the Next.js container
+	// +kubebuilder:default="512Mi"
+	// +optional
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+
+	// CPU limit for the Next.js container
+	// +kubebuilder:default="1000m"
+	// +optional
+	CPULimit *resource.Quantity `json:"cpuLimit,omitempty"`
+
+	// Memory limit for the Next.js container
+	// +kubebuilder:default="1Gi"
+	// +optional
+	MemoryLimit *resource.Quantity `json:"memoryLimit,omitempty"`
 }
 
+type ProbesSpec struct {
+	// Readiness probe, defaults to /api/health on port 3000
+	// +optional
+	Readiness *ProbeSpec `json:"readiness,omitempty"`
+
+	// Liveness probe, defaults to /api/health on port 3000
+	// +optional
+	Liveness *ProbeSpec `json:"liveness,omitempty"`
+
+	// Startup probe, only added when set. Useful for slow-booting bytecode binaries.
+	// +optional
+	Startup *ProbeSpec `json:"startup,omitempty"`
+}
+
+// ProbeSpec configures an HTTP GET probe. Unset fields fall back to the operator
+// defaults; the timings are pointers so an explicit 0 is kept.
+type ProbeSpec struct {
+	// HTTP path to probe
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Container port to probe. Knative probes the user container on its
+	// serving port, so only the app's port 3000 is accepted.
+	// +kubebuilder:validation:XValidation:rule="self == 3000",message="must be the app container port 3000"
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+type RolloutSpec struct {
+	// Traffic steps applied to a new revision once it is ready. After the
+	// last step the revision is promoted to 100%.
+	// +kubebuilder:validation:MinItems=1
+	Steps []RolloutStep `json:"steps"`
+}
+
+type RolloutStep struct {
+	// Percentage of traffic sent to the new revision during this step
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// How long to hold this step before moving on, e.g. 5m
+	// +optional
+	Pause metav1.Duration `json:"pause,omitempty"`
+}
+
+type DomainSpec struct {
+	// Fully qualified host name, e.g. app.example.com
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Host string `json:"host"`
+
+	// Next.js i18n locale served on this domain, matching the defaultLocale
+	// of the corresponding entry in i18n.domains
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// TLS termination for the domain
+	// +optional
+	TLS *DomainTLSSpec `json:"tls,omitempty"`
+}
+
+// DomainTLSSpec configures the certificate used for a custom domain. With an
+// issuerRef, a cert-manager Certificate is created into secretName; without
+// one, secretName must already hold the certificate.
+type DomainTLSSpec struct {
+	// Secret holding the TLS certificate, defaults to <host>-tls
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// cert-manager issuer to request the certificate from
+	// +optional
+	IssuerRef *IssuerRef `json:"issuerRef,omitempty"`
+}
+
+type IssuerRef struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+type RollbackSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// How long a new revision may take to become ready before traffic is
+	// pinned back to the last good revision
+	// +kubebuilder:default="10m"
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+type ObservabilitySpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Path the Next.js server exposes Prometheus metrics on
+	// +kubebuilder:default="/metrics"
+	// +optional
+	MetricsPath string `json:"metricsPath,omitempty"`
+
+	// +optional
+	Prometheus *PrometheusSpec `json:"prometheus,omitempty"`
+
+	// +optional
+	Grafana *GrafanaSpec `json:"grafana,omitempty"`
+
+	// PrometheusRule alerting for the app
+	// +optional
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
+}
+
+type PrometheusSpec struct {
+	// +kubebuilder:default="15s"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+}
+
+// AlertsSpec configures the generated PrometheusRule. Unset thresholds use the defaults.
+type AlertsSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Fire when the share of 5xx responses exceeds this percentage
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=5
+	// +optional
+	ErrorRatePercent int32 `json:"errorRatePercent,omitempty"`
+
+	// Fire when p95 request latency exceeds this many milliseconds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=2000
+	// +optional
+	LatencyP95Milliseconds int32 `json:"latencyP95Milliseconds,omitempty"`
+
+	// Fire when p95 cold-start duration exceeds this many seconds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// +optional
+	ColdStartP95Seconds int32 `json:"coldStartP95Seconds,omitempty"`
+
+	// Fire when the revalidation consumer group lags by more than this many messages
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1000
+	// +optional
+	RevalidationLagMessages int32 `json:"revalidationLagMessages,omitempty"`
+
+	// How long a condition must hold before an alert fires
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	For string `json:"for,omitempty"`
+}
+
+type GrafanaSpec struct {
+	// Deploy the dashboard ConfigMap, defaults to true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// Storage providers, matching the TypeScript StorageProvider type.
+const (
+	StorageProviderGCS   = "gcs"
+	StorageProviderS3    = "s3"
+	StorageProviderAzure = "azure"
+	StorageProviderMinio = "minio"
+)
+
+// Keys expected in the storage credentials Secret, per provider.
+const (
+	// StorageCredentialsGCSKey holds the GCS service account JSON key.
+	StorageCredentialsGCSKey = "key.json"
+	// StorageCredentialsAccessKey and StorageCredentialsSecretKey hold S3/MinIO credentials.
+	StorageCredentialsAccessKey = "accessKey"
+	StorageCredentialsSecretKey = "secretKey"
+	// StorageCredentialsConnectionStringKey holds the Azure Storage connection string.
+	StorageCredentialsConnectionStringKey = "connectionString"
+)
+
+// +kubebuilder:validation:XValidation:rule="self.provider != 'minio' || has(self.endpoint)",message="endpoint is required for the minio provider"
 type StorageSpec struct {
+	// +kubebuilder:validation:Enum=gcs;s3;azure;minio
 	Provider string `json:"provider,omitempty"`
 	Bucket   string `json:"bucket,omitempty"`
+
+	// Bucket region for S3-compatible providers, defaults to us-east-1
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Custom endpoint for MinIO/S3-compatible or Azure storage
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Public CDN URL static assets are served from, exposed to the app as ASSET_PREFIX
+	// +optional
+	PublicURL string `json:"publicUrl,omitempty"`
+
+	// Name of a Secret holding provider credentials: key.json for gcs,
+	// accessKey/secretKey for s3 and minio, connectionString for azure.
+	// Leave empty to rely on workload identity / IAM.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
 }
 
+// Cache providers. Redis and DynamoDB back the tag cache; Redis or the
+// object storage bucket from spec.storage back the incremental cache.
+const (
+	CacheProviderRedis    = "redis"
+	CacheProviderDynamoDB = "dynamodb"
+	CacheProviderGCS      = "gcs"
+	CacheProviderS3       = "s3"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.incrementalCache) || self.incrementalCache.provider != 'dynamodb'",message="dynamodb can only back the tag cache"
+// +kubebuilder:validation:XValidation:rule="!has(self.tagCache) || self.tagCache.provider in ['redis', 'dynamodb']",message="the tag cache must use redis or dynamodb"
 type CacheSpec struct {
-	Provider            string `json:"provider,omitempty"`
-	URL                 string `json:"url,omitempty"`
-	EnableBytecodeCache bool   `json:"enableBytecodeCache,omitempty"`
-	BytecodeCacheSize   string `json:"bytecodeCacheSize,omitempty"`
+	// Shorthand for a Redis incremental and tag cache at url. Ignored for
+	// whichever of incrementalCache/tagCache is set explicitly.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Backend for the ISR/fetch incremental cache
+	// +optional
+	IncrementalCache *CacheBackendSpec `json:"incrementalCache,omitempty"`
+
+	// Backend for the revalidation tag cache
+	// +optional
+	TagCache *CacheBackendSpec `json:"tagCache,omitempty"`
+
+	EnableBytecodeCache bool `json:"enableBytecodeCache,omitempty"`
+
+	// Size of the bytecode cache PVC, as a Kubernetes quantity (e.g. 512Mi)
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	// +optional
+	BytecodeCacheSize string `json:"bytecodeCacheSize,omitempty"`
 }
 
+// CacheBackendSpec selects a cache provider and carries its settings.
+// The gcs and s3 providers reuse the bucket configured in spec.storage.
+// +kubebuilder:validation:XValidation:rule="self.provider != 'redis' || has(self.redis)",message="redis settings are required for the redis provider"
+// +kubebuilder:validation:XValidation:rule="self.provider != 'dynamodb' || has(self.dynamodb)",message="dynamodb settings are required for the dynamodb provider"
+type CacheBackendSpec struct {
+	// +kubebuilder:validation:Enum=redis;dynamodb;gcs;s3
+	Provider string `json:"provider"`
+
+	// +optional
+	Redis *RedisCacheSpec `json:"redis,omitempty"`
+
+	// +optional
+	DynamoDB *DynamoDBCacheSpec `json:"dynamodb,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.url) != has(self.urlSecretRef)",message="exactly one of url or urlSecretRef must be set"
+type RedisCacheSpec struct {
+	// Redis connection URL. Prefer urlSecretRef when it embeds credentials.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Secret key holding the full Redis connection URL, key defaults to url
+	// +optional
+	URLSecretRef *SecretKeyRef `json:"urlSecretRef,omitempty"`
+
+	// Secret key holding the Redis password, spliced into url at runtime.
+	// The key defaults to password and the value must be URL-safe.
+	// +optional
+	PasswordSecretRef *SecretKeyRef `json:"passwordSecretRef,omitempty"`
+
+	// Prefix for every cache key, defaults to <namespace>/<name> so several
+	// NextApps can share one Redis
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// +optional
+	TLS *RedisTLSSpec `json:"tls,omitempty"`
+}
+
+type RedisTLSSpec struct {
+	// Connect over TLS (rediss://)
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Secret key holding the PEM CA bundle used to verify the Redis server.
+	// The key defaults to ca.crt.
+	// +optional
+	CASecretRef *SecretKeyRef `json:"caSecretRef,omitempty"`
+}
+
+type DynamoDBCacheSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	TableName string `json:"tableName"`
+
+	// +kubebuilder:validation:MinLength=1
+	Region string `json:"region"`
+
+	// Name of a Secret with accessKey/secretKey. Leave empty to rely on IAM.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// Revalidation queues. kafka publishes to a topic consumed by a KafkaSource;
+// broker posts CloudEvents to a Knative Eventing Broker and a Trigger
+// delivers them.
+const (
+	RevalidationQueueKafka  = "kafka"
+	RevalidationQueueBroker = "broker"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.queue) || self.queue != 'kafka' || (has(self.kafkaBrokerUrl) && size(self.kafkaBrokerUrl) > 0)",message="kafkaBrokerUrl is required for the kafka queue",fieldPath=".kafkaBrokerUrl"
 type RevalidationSpec struct {
+	// +kubebuilder:validation:Enum=kafka;broker
 	Queue          string `json:"queue,omitempty"`
 	KafkaBrokerUrl string `json:"kafkaBrokerUrl,omitempty"`
+
+	// Knative Eventing Broker used by the broker queue
+	// +kubebuilder:default=default
+	// +optional
+	Broker string `json:"broker,omitempty"`
+
+	// Sink that receives revalidation events from the queue
+	// +optional
+	Revalidator *RevalidatorSpec `json:"revalidator,omitempty"`
+
+	// Kafka topic revalidation events are published to
+	// +optional
+	Topic *KafkaTopicSpec `json:"topic,omitempty"`
+
+	// SASL and TLS settings for brokers that require authentication
+	// +optional
+	Auth *KafkaAuthSpec `json:"auth,omitempty"`
+
+	// Retry and dead-letter policy for delivering revalidation events
+	// +optional
+	Delivery *DeliverySpec `json:"delivery,omitempty"`
+}
+
+type DeliverySpec struct {
+	// Number of retries before an event is dead-lettered
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retry *int32 `json:"retry,omitempty"`
+
+	// +kubebuilder:validation:Enum=linear;exponential
+	// +optional
+	BackoffPolicy string `json:"backoffPolicy,omitempty"`
+
+	// Delay before the first retry, e.g. 200ms
+	// +optional
+	BackoffDelay *metav1.Duration `json:"backoffDelay,omitempty"`
+
+	// Where events that exhausted their retries are sent. Defaults to the
+	// operator's dead-letter receiver, which counts them in
+	// status.deadLetter.
+	// +optional
+	DeadLetterSinkURI string `json:"deadLetterSinkUri,omitempty"`
+}
+
+// Keys read from the Kafka SASL credentials Secret, following the Knative
+// KafkaSource convention.
+const (
+	KafkaSASLUserKey     = "user"
+	KafkaSASLPasswordKey = "password"
+	KafkaSASLTypeKey     = "saslType"
+)
+
+type KafkaAuthSpec struct {
+	// +optional
+	SASL *KafkaSASLSpec `json:"sasl,omitempty"`
+
+	// +optional
+	TLS *KafkaTLSSpec `json:"tls,omitempty"`
+}
+
+type KafkaSASLSpec struct {
+	// Secret with the user, password and saslType (PLAIN, SCRAM-SHA-256 or
+	// SCRAM-SHA-512) keys
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.certSecretRef) == has(self.keySecretRef)",message="certSecretRef and keySecretRef must be set together"
+type KafkaTLSSpec struct {
+	// Connect to the brokers over TLS (SSL or SASL_SSL)
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Secret key holding the PEM CA bundle used to verify the brokers.
+	// The key defaults to ca.crt.
+	// +optional
+	CASecretRef *SecretKeyRef `json:"caSecretRef,omitempty"`
+
+	// Client certificate for mutual TLS. The key defaults to tls.crt.
+	// +optional
+	CertSecretRef *SecretKeyRef `json:"certSecretRef,omitempty"`
+
+	// Client private key for mutual TLS. The key defaults to tls.key.
+	// +optional
+	KeySecretRef *SecretKeyRef `json:"keySecretRef,omitempty"`
+}
+
+type KafkaTopicSpec struct {
+	// Topic name, defaults to <app>-isr-revalidation like the TypeScript
+	// KafkaQueueConfig
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]+$`
+	// +kubebuilder:validation:MaxLength=249
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Partitions int32 `json:"partitions,omitempty"`
+
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
+
+	// How long messages are kept, e.g. 24h. Defaults to the broker setting.
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
+
+	// Provision the topic as a Strimzi KafkaTopic
+	// +optional
+	Strimzi *StrimziTopicSpec `json:"strimzi,omitempty"`
+}
+
+type StrimziTopicSpec struct {
+	// Name of the Strimzi Kafka cluster, set as the strimzi.io/cluster label
+	// +kubebuilder:validation:MinLength=1
+	Cluster string `json:"cluster"`
+
+	// Namespace watched by the Strimzi topic operator, defaults to the
+	// NextApp namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RevalidatorSpec selects where revalidation events are delivered. With an
+// image, a dedicated <app>-revalidator Knative Service is run that calls back
+// into the app; without one, the app itself receives the events on path.
+type RevalidatorSpec struct {
+	// Image of a dedicated revalidator service
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Route on the app that handles revalidation CloudEvents, used when no
+	// image is set
+	// +kubebuilder:default="/api/revalidate"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
 type SecretsSpec struct {
 	EnvFrom []string `json:"envFrom,omitempty"`
+
+	// Map of explicit ENV_VAR -> Secret key, injected via valueFrom.secretKeyRef
+	// +optional
+	EnvMap map[string]SecretKeyRef `json:"envMap,omitempty"`
+}
+
+// SecretKeyRef points at a single key within a Kubernetes Secret.
+type SecretKeyRef struct {
+	// Name of the Kubernetes Secret resource
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key within the Secret. The default depends on where the reference is
+	// used; for envMap it is the environment variable name.
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // NextAppStatus defines the observed state of NextApp.
@@ -99,13 +666,44 @@ type NextAppStatus struct {
 
 	URL string `json:"url,omitempty"`
 
+	// Generation of the spec the status was last computed from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Latest revision of the Knative Service that became ready
+	// +optional
+	LatestReadyRevision string `json:"latestReadyRevision,omitempty"`
+
+	// Traffic split currently served by the Knative Service route
+	// +optional
+	Traffic []TrafficStatus `json:"traffic,omitempty"`
+
+	// Progress of the current traffic rollout
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// The failed revision traffic was rolled back from, if any
+	// +optional
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+
+	// Readiness of each custom domain
+	// +optional
+	Domains []DomainStatus `json:"domains,omitempty"`
+
+	// Revalidation events counted by the operator's dead-letter receiver
+	// +optional
+	DeadLetter *DeadLetterStatus `json:"deadLetter,omitempty"`
+
 	// conditions represent the current state of the NextApp resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
-	// Standard condition types include:
-	// - "Available": the resource is fully functional
-	// - "Progressing": the resource is being created or updated
-	// - "Degraded": the resource failed to reach or maintain its desired state
+	// Condition types include:
+	// - "Ready": the app is serving and every enabled feature is ready
+	// - "ServiceReady": the Knative Service is ready
+	// - "CacheReady": the bytecode cache volume is bound
+	// - "RevalidationReady": revalidation events reach their sink
+	// - "Degraded": a sub-resource failed or traffic was rolled back
+	// - "DriftDetected": an owned object was edited by hand
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -114,8 +712,87 @@ type NextAppStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+type RolloutStatus struct {
+	// Revision currently receiving the remaining traffic
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// Revision being rolled out, empty when no rollout is in progress
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+
+	// Index into spec.rollout.steps of the current step
+	CurrentStep int32 `json:"currentStep,omitempty"`
+
+	// Traffic percentage currently sent to the canary revision
+	CanaryPercent int32 `json:"canaryPercent,omitempty"`
+
+	// When the current step started
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+}
+
+type TrafficStatus struct {
+	// Revision receiving this share of traffic
+	RevisionName string `json:"revisionName,omitempty"`
+
+	// Tag addressing the revision directly, if any
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// Whether the target follows the latest ready revision
+	// +optional
+	LatestRevision bool `json:"latestRevision,omitempty"`
+
+	// Percentage of requests routed to the revision
+	Percent int64 `json:"percent"`
+
+	// URL of the tagged revision
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+type DomainStatus struct {
+	Host   string `json:"host"`
+	Locale string `json:"locale,omitempty"`
+	URL    string `json:"url,omitempty"`
+
+	// Ready condition of the DomainMapping: True, False or Unknown
+	Ready metav1.ConditionStatus `json:"ready"`
+
+	// Why the domain is not ready yet
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type DeadLetterStatus struct {
+	// Total number of dead-lettered revalidation events
+	Events int64 `json:"events"`
+
+	// When the last event was dead-lettered
+	// +optional
+	LastEventTime *metav1.Time `json:"lastEventTime,omitempty"`
+}
+
+type RollbackStatus struct {
+	// Latest created revision that failed to become ready
+	FailedRevision string `json:"failedRevision"`
+
+	// Image of the failed revision
+	FailedImage string `json:"failedImage,omitempty"`
+
+	// Last good revision traffic is pinned to
+	TargetRevision string `json:"targetRevision,omitempty"`
+
+	// When the rollback happened
+	Time metav1.Time `json:"time"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`,priority=1
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.status.latestReadyRevision`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:storageversion
 
 // NextApp is the Schema for the nextapps API
 type NextApp struct {
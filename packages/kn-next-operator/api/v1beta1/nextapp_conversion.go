@@ -0,0 +1,593 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// V1alpha1SpecAnnotation stashes a v1alpha1 spec that v1beta1 cannot
+// represent, such as a provider string without its settings, so converting
+// back to v1alpha1 restores it exactly.
+const V1alpha1SpecAnnotation = "apps.kn-next.dev/v1alpha1-spec"
+
+// ConvertTo converts this NextApp (v1beta1) to the Hub version (v1alpha1).
+func (src *NextApp) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.NextApp)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = specToHub(&src.Spec)
+	if stashed, ok := stashedSpec(src); ok {
+		dst.Spec = stashed
+	}
+	delete(dst.Annotations, V1alpha1SpecAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	dst.Status = statusToHub(&src.Status)
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this version.
+func (dst *NextApp) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.NextApp)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = specFromHub(&src.Spec)
+	delete(dst.Annotations, V1alpha1SpecAnnotation)
+	if roundTrip := specToHub(&dst.Spec); !equality.Semantic.DeepEqual(roundTrip, src.Spec) {
+		data, err := json.Marshal(src.Spec)
+		if err != nil {
+			return fmt.Errorf("stash v1alpha1 spec: %w", err)
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = make(map[string]string)
+		}
+		dst.Annotations[V1alpha1SpecAnnotation] = string(data)
+	}
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	dst.Status = statusFromHub(&src.Status)
+	return nil
+}
+
+// stashedSpec returns the v1alpha1 spec stashed by ConvertFrom, as long as
+// the v1beta1 spec has not been edited since. After an edit the stash is
+// stale and the v1beta1 spec wins.
+func stashedSpec(src *NextApp) (v1alpha1.NextAppSpec, bool) {
+	var stashed v1alpha1.NextAppSpec
+	data, ok := src.Annotations[V1alpha1SpecAnnotation]
+	if !ok || json.Unmarshal([]byte(data), &stashed) != nil {
+		return stashed, false
+	}
+	if !equality.Semantic.DeepEqual(specFromHub(&stashed), src.Spec) {
+		return stashed, false
+	}
+	return stashed, true
+}
+
+func specToHub(in *NextAppSpec) v1alpha1.NextAppSpec {
+	out := v1alpha1.NextAppSpec{
+		Image:          in.Image,
+		Scaling:        (*v1alpha1.ScalingSpec)(in.Scaling.DeepCopy()),
+		Storage:        storageToHub(in.Storage),
+		Cache:          cacheToHub(in.Cache),
+		Secrets:        secretsToHub(in.Secrets),
+		Rollout:        rolloutToHub(in.Rollout),
+		Rollback:       (*v1alpha1.RollbackSpec)(in.Rollback.DeepCopy()),
+		DeletionPolicy: in.DeletionPolicy,
+		DriftPolicy:    in.DriftPolicy,
+	}
+	if in.Revalidation != nil {
+		out.Revalidation = revalidationToHub(in.Revalidation)
+	}
+	if in.Preview != nil {
+		out.Preview = &v1alpha1.PreviewSpec{
+			Enabled: true,
+			Branch:  in.Preview.Branch,
+			PRID:    in.Preview.PullRequest,
+		}
+	}
+	if in.Probes != nil {
+		out.Probes = &v1alpha1.ProbesSpec{
+			Readiness: (*v1alpha1.ProbeSpec)(in.Probes.Readiness.DeepCopy()),
+			Liveness:  (*v1alpha1.ProbeSpec)(in.Probes.Liveness.DeepCopy()),
+			Startup:   (*v1alpha1.ProbeSpec)(in.Probes.Startup.DeepCopy()),
+		}
+	}
+	if in.Observability != nil {
+		out.Observability = &v1alpha1.ObservabilitySpec{
+			Enabled:     in.Observability.Enabled,
+			MetricsPath: in.Observability.MetricsPath,
+			Prometheus:  (*v1alpha1.PrometheusSpec)(in.Observability.Prometheus.DeepCopy()),
+			Grafana:     (*v1alpha1.GrafanaSpec)(in.Observability.Grafana.DeepCopy()),
+			Alerts:      (*v1alpha1.AlertsSpec)(in.Observability.Alerts.DeepCopy()),
+		}
+	}
+	for _, domain := range in.Domains {
+		hub := v1alpha1.DomainSpec{Host: domain.Host, Locale: domain.Locale}
+		if domain.TLS != nil {
+			hub.TLS = &v1alpha1.DomainTLSSpec{
+				SecretName: domain.TLS.SecretName,
+				IssuerRef:  (*v1alpha1.IssuerRef)(domain.TLS.IssuerRef.DeepCopy()),
+			}
+		}
+		out.Domains = append(out.Domains, hub)
+	}
+	return out
+}
+
+func specFromHub(in *v1alpha1.NextAppSpec) NextAppSpec {
+	out := NextAppSpec{
+		Image:          in.Image,
+		Scaling:        (*ScalingSpec)(in.Scaling.DeepCopy()),
+		Storage:        storageFromHub(in.Storage),
+		Cache:          cacheFromHub(in.Cache),
+		Secrets:        secretsFromHub(in.Secrets),
+		Rollout:        rolloutFromHub(in.Rollout),
+		Rollback:       (*RollbackSpec)(in.Rollback.DeepCopy()),
+		DeletionPolicy: in.DeletionPolicy,
+		DriftPolicy:    in.DriftPolicy,
+	}
+	if in.Revalidation != nil {
+		out.Revalidation = revalidationFromHub(in.Revalidation)
+	}
+	if in.Preview != nil && in.Preview.Enabled {
+		out.Preview = &PreviewSpec{PullRequest: in.Preview.PRID, Branch: in.Preview.Branch}
+	}
+	if in.Probes != nil {
+		out.Probes = &ProbesSpec{
+			Readiness: (*ProbeSpec)(in.Probes.Readiness.DeepCopy()),
+			Liveness:  (*ProbeSpec)(in.Probes.Liveness.DeepCopy()),
+			Startup:   (*ProbeSpec)(in.Probes.Startup.DeepCopy()),
+		}
+	}
+	if in.Observability != nil {
+		out.Observability = &ObservabilitySpec{
+			Enabled:     in.Observability.Enabled,
+			MetricsPath: in.Observability.MetricsPath,
+			Prometheus:  (*PrometheusSpec)(in.Observability.Prometheus.DeepCopy()),
+			Grafana:     (*GrafanaSpec)(in.Observability.Grafana.DeepCopy()),
+			Alerts:      (*AlertsSpec)(in.Observability.Alerts.DeepCopy()),
+		}
+	}
+	for _, domain := range in.Domains {
+		spoke := DomainSpec{Host: domain.Host, Locale: domain.Locale}
+		if domain.TLS != nil {
+			spoke.TLS = &DomainTLSSpec{
+				SecretName: domain.TLS.SecretName,
+				IssuerRef:  (*IssuerRef)(domain.TLS.IssuerRef.DeepCopy()),
+			}
+		}
+		out.Domains = append(out.Domains, spoke)
+	}
+	return out
+}
+
+func storageToHub(in *StorageSpec) *v1alpha1.StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.StorageSpec{PublicURL: in.PublicURL}
+	switch {
+	case in.GCS != nil:
+		out.Provider = v1alpha1.StorageProviderGCS
+		out.Bucket = in.GCS.Bucket
+		out.CredentialsSecret = secretName(in.GCS.CredentialsSecretRef)
+	case in.S3 != nil:
+		out.Provider = v1alpha1.StorageProviderS3
+		out.Bucket = in.S3.Bucket
+		out.Region = in.S3.Region
+		out.Endpoint = in.S3.Endpoint
+		out.CredentialsSecret = secretName(in.S3.CredentialsSecretRef)
+	case in.Azure != nil:
+		out.Provider = v1alpha1.StorageProviderAzure
+		out.Bucket = in.Azure.Container
+		out.Endpoint = in.Azure.Endpoint
+		out.CredentialsSecret = secretName(in.Azure.CredentialsSecretRef)
+	case in.MinIO != nil:
+		out.Provider = v1alpha1.StorageProviderMinio
+		out.Bucket = in.MinIO.Bucket
+		out.Region = in.MinIO.Region
+		out.Endpoint = in.MinIO.Endpoint
+		out.CredentialsSecret = secretName(in.MinIO.CredentialsSecretRef)
+	}
+	return out
+}
+
+func storageFromHub(in *v1alpha1.StorageSpec) *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := &StorageSpec{PublicURL: in.PublicURL}
+	credentials := secretReference(in.CredentialsSecret)
+	switch in.Provider {
+	case v1alpha1.StorageProviderGCS:
+		out.GCS = &GCSStorageSpec{Bucket: in.Bucket, CredentialsSecretRef: credentials}
+	case v1alpha1.StorageProviderS3:
+		out.S3 = &S3StorageSpec{
+			Bucket:               in.Bucket,
+			Region:               in.Region,
+			Endpoint:             in.Endpoint,
+			CredentialsSecretRef: credentials,
+		}
+	case v1alpha1.StorageProviderAzure:
+		out.Azure = &AzureStorageSpec{Container: in.Bucket, Endpoint: in.Endpoint, CredentialsSecretRef: credentials}
+	case v1alpha1.StorageProviderMinio:
+		out.MinIO = &MinIOStorageSpec{
+			Bucket:               in.Bucket,
+			Endpoint:             in.Endpoint,
+			Region:               in.Region,
+			CredentialsSecretRef: credentials,
+		}
+	}
+	return out
+}
+
+func cacheToHub(in *CacheSpec) *v1alpha1.CacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.CacheSpec{
+		IncrementalCache: cacheBackendToHub(in.Incremental),
+		TagCache:         cacheBackendToHub(in.Tag),
+	}
+	if in.Bytecode != nil {
+		out.EnableBytecodeCache = true
+		if in.Bytecode.Size != nil {
+			out.BytecodeCacheSize = in.Bytecode.Size.String()
+		}
+	}
+	return out
+}
+
+// cacheFromHub expands the v1alpha1 provider/url shorthand into explicit
+// Redis backends, the same way the reconciler resolves it.
+func cacheFromHub(in *v1alpha1.CacheSpec) *CacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := &CacheSpec{
+		Incremental: cacheBackendFromHub(in.IncrementalCache),
+		Tag:         cacheBackendFromHub(in.TagCache),
+	}
+	if in.Provider == v1alpha1.CacheProviderRedis && in.URL != "" {
+		if out.Incremental == nil {
+			out.Incremental = &CacheBackendSpec{Redis: &RedisCacheSpec{URL: in.URL}}
+		}
+		if out.Tag == nil {
+			out.Tag = &CacheBackendSpec{Redis: &RedisCacheSpec{URL: in.URL}}
+		}
+	}
+	if in.EnableBytecodeCache {
+		out.Bytecode = &BytecodeCacheSpec{}
+		if size, err := resource.ParseQuantity(in.BytecodeCacheSize); err == nil {
+			out.Bytecode.Size = &size
+		}
+	}
+	return out
+}
+
+func cacheBackendToHub(in *CacheBackendSpec) *v1alpha1.CacheBackendSpec {
+	if in == nil {
+		return nil
+	}
+	switch {
+	case in.Redis != nil:
+		return &v1alpha1.CacheBackendSpec{Provider: v1alpha1.CacheProviderRedis, Redis: redisToHub(in.Redis)}
+	case in.DynamoDB != nil:
+		return &v1alpha1.CacheBackendSpec{
+			Provider: v1alpha1.CacheProviderDynamoDB,
+			DynamoDB: &v1alpha1.DynamoDBCacheSpec{
+				TableName:         in.DynamoDB.TableName,
+				Region:            in.DynamoDB.Region,
+				CredentialsSecret: secretName(in.DynamoDB.CredentialsSecretRef),
+			},
+		}
+	case in.GCS != nil:
+		return &v1alpha1.CacheBackendSpec{Provider: v1alpha1.CacheProviderGCS}
+	case in.S3 != nil:
+		return &v1alpha1.CacheBackendSpec{Provider: v1alpha1.CacheProviderS3}
+	}
+	return &v1alpha1.CacheBackendSpec{}
+}
+
+func cacheBackendFromHub(in *v1alpha1.CacheBackendSpec) *CacheBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := &CacheBackendSpec{}
+	switch in.Provider {
+	case v1alpha1.CacheProviderRedis:
+		out.Redis = &RedisCacheSpec{}
+		if in.Redis != nil {
+			out.Redis = redisFromHub(in.Redis)
+		}
+	case v1alpha1.CacheProviderDynamoDB:
+		out.DynamoDB = &DynamoDBCacheSpec{}
+		if ddb := in.DynamoDB; ddb != nil {
+			out.DynamoDB = &DynamoDBCacheSpec{
+				TableName:            ddb.TableName,
+				Region:               ddb.Region,
+				CredentialsSecretRef: secretReference(ddb.CredentialsSecret),
+			}
+		}
+	case v1alpha1.CacheProviderGCS:
+		out.GCS = &BucketCacheSpec{}
+	case v1alpha1.CacheProviderS3:
+		out.S3 = &BucketCacheSpec{}
+	}
+	return out
+}
+
+func redisToHub(in *RedisCacheSpec) *v1alpha1.RedisCacheSpec {
+	out := &v1alpha1.RedisCacheSpec{
+		URL:               in.URL,
+		URLSecretRef:      (*v1alpha1.SecretKeyRef)(in.URLSecretRef.DeepCopy()),
+		PasswordSecretRef: (*v1alpha1.SecretKeyRef)(in.PasswordSecretRef.DeepCopy()),
+		KeyPrefix:         in.KeyPrefix,
+	}
+	if in.TLS != nil {
+		out.TLS = &v1alpha1.RedisTLSSpec{
+			Enabled:     in.TLS.Enabled,
+			CASecretRef: (*v1alpha1.SecretKeyRef)(in.TLS.CASecretRef.DeepCopy()),
+		}
+	}
+	return out
+}
+
+func redisFromHub(in *v1alpha1.RedisCacheSpec) *RedisCacheSpec {
+	out := &RedisCacheSpec{
+		URL:               in.URL,
+		URLSecretRef:      (*SecretKeyRef)(in.URLSecretRef.DeepCopy()),
+		PasswordSecretRef: (*SecretKeyRef)(in.PasswordSecretRef.DeepCopy()),
+		KeyPrefix:         in.KeyPrefix,
+	}
+	if in.TLS != nil {
+		out.TLS = &RedisTLSSpec{
+			Enabled:     in.TLS.Enabled,
+			CASecretRef: (*SecretKeyRef)(in.TLS.CASecretRef.DeepCopy()),
+		}
+	}
+	return out
+}
+
+func revalidationToHub(in *RevalidationSpec) *v1alpha1.RevalidationSpec {
+	out := &v1alpha1.RevalidationSpec{
+		// v1alpha1 defaults broker even for the kafka queue
+		Broker:      "default",
+		Revalidator: (*v1alpha1.RevalidatorSpec)(in.Revalidator.DeepCopy()),
+		Delivery:    (*v1alpha1.DeliverySpec)(in.Delivery.DeepCopy()),
+	}
+	switch {
+	case in.Kafka != nil:
+		out.Queue = v1alpha1.RevalidationQueueKafka
+		out.KafkaBrokerUrl = in.Kafka.BootstrapServers
+		out.Topic = kafkaTopicToHub(in.Kafka.Topic)
+		out.Auth = kafkaAuthToHub(in.Kafka.Auth)
+	case in.Broker != nil:
+		out.Queue = v1alpha1.RevalidationQueueBroker
+		if in.Broker.Name != "" {
+			out.Broker = in.Broker.Name
+		}
+	}
+	return out
+}
+
+func revalidationFromHub(in *v1alpha1.RevalidationSpec) *RevalidationSpec {
+	out := &RevalidationSpec{
+		Revalidator: (*RevalidatorSpec)(in.Revalidator.DeepCopy()),
+		Delivery:    (*DeliverySpec)(in.Delivery.DeepCopy()),
+	}
+	switch in.Queue {
+	case v1alpha1.RevalidationQueueKafka:
+		out.Kafka = &KafkaQueueSpec{
+			BootstrapServers: in.KafkaBrokerUrl,
+			Topic:            kafkaTopicFromHub(in.Topic),
+			Auth:             kafkaAuthFromHub(in.Auth),
+		}
+	case v1alpha1.RevalidationQueueBroker:
+		out.Broker = &BrokerQueueSpec{Name: in.Broker}
+	}
+	return out
+}
+
+func kafkaTopicToHub(in *KafkaTopicSpec) *v1alpha1.KafkaTopicSpec {
+	if in == nil {
+		return nil
+	}
+	return &v1alpha1.KafkaTopicSpec{
+		Name:              in.Name,
+		Partitions:        in.Partitions,
+		ReplicationFactor: in.ReplicationFactor,
+		Retention:         in.Retention.DeepCopy(),
+		Strimzi:           (*v1alpha1.StrimziTopicSpec)(in.Strimzi.DeepCopy()),
+	}
+}
+
+func kafkaTopicFromHub(in *v1alpha1.KafkaTopicSpec) *KafkaTopicSpec {
+	if in == nil {
+		return nil
+	}
+	return &KafkaTopicSpec{
+		Name:              in.Name,
+		Partitions:        in.Partitions,
+		ReplicationFactor: in.ReplicationFactor,
+		Retention:         in.Retention.DeepCopy(),
+		Strimzi:           (*StrimziTopicSpec)(in.Strimzi.DeepCopy()),
+	}
+}
+
+func kafkaAuthToHub(in *KafkaAuthSpec) *v1alpha1.KafkaAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.KafkaAuthSpec{}
+	if in.SASL != nil {
+		out.SASL = &v1alpha1.KafkaSASLSpec{CredentialsSecret: in.SASL.CredentialsSecretRef.Name}
+	}
+	if in.TLS != nil {
+		out.TLS = &v1alpha1.KafkaTLSSpec{
+			Enabled:       in.TLS.Enabled,
+			CASecretRef:   (*v1alpha1.SecretKeyRef)(in.TLS.CASecretRef.DeepCopy()),
+			CertSecretRef: (*v1alpha1.SecretKeyRef)(in.TLS.CertSecretRef.DeepCopy()),
+			KeySecretRef:  (*v1alpha1.SecretKeyRef)(in.TLS.KeySecretRef.DeepCopy()),
+		}
+	}
+	return out
+}
+
+func kafkaAuthFromHub(in *v1alpha1.KafkaAuthSpec) *KafkaAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := &KafkaAuthSpec{}
+	if in.SASL != nil {
+		out.SASL = &KafkaSASLSpec{CredentialsSecretRef: SecretReference{Name: in.SASL.CredentialsSecret}}
+	}
+	if in.TLS != nil {
+		out.TLS = &KafkaTLSSpec{
+			Enabled:       in.TLS.Enabled,
+			CASecretRef:   (*SecretKeyRef)(in.TLS.CASecretRef.DeepCopy()),
+			CertSecretRef: (*SecretKeyRef)(in.TLS.CertSecretRef.DeepCopy()),
+			KeySecretRef:  (*SecretKeyRef)(in.TLS.KeySecretRef.DeepCopy()),
+		}
+	}
+	return out
+}
+
+func secretsToHub(in *SecretsSpec) *v1alpha1.SecretsSpec {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.SecretsSpec{}
+	for _, ref := range in.EnvFrom {
+		out.EnvFrom = append(out.EnvFrom, ref.Name)
+	}
+	if in.EnvMap != nil {
+		out.EnvMap = make(map[string]v1alpha1.SecretKeyRef, len(in.EnvMap))
+		for env, ref := range in.EnvMap {
+			out.EnvMap[env] = v1alpha1.SecretKeyRef(ref)
+		}
+	}
+	return out
+}
+
+func secretsFromHub(in *v1alpha1.SecretsSpec) *SecretsSpec {
+	if in == nil {
+		return nil
+	}
+	out := &SecretsSpec{}
+	for _, name := range in.EnvFrom {
+		out.EnvFrom = append(out.EnvFrom, SecretReference{Name: name})
+	}
+	if in.EnvMap != nil {
+		out.EnvMap = make(map[string]SecretKeyRef, len(in.EnvMap))
+		for env, ref := range in.EnvMap {
+			out.EnvMap[env] = SecretKeyRef(ref)
+		}
+	}
+	return out
+}
+
+func rolloutToHub(in *RolloutSpec) *v1alpha1.RolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.RolloutSpec{}
+	for _, step := range in.Steps {
+		out.Steps = append(out.Steps, v1alpha1.RolloutStep(step))
+	}
+	return out
+}
+
+func rolloutFromHub(in *v1alpha1.RolloutSpec) *RolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := &RolloutSpec{}
+	for _, step := range in.Steps {
+		out.Steps = append(out.Steps, RolloutStep(step))
+	}
+	return out
+}
+
+func statusToHub(in *NextAppStatus) v1alpha1.NextAppStatus {
+	out := v1alpha1.NextAppStatus{
+		URL:                 in.URL,
+		ObservedGeneration:  in.ObservedGeneration,
+		LatestReadyRevision: in.LatestReadyRevision,
+		Rollout:             (*v1alpha1.RolloutStatus)(in.Rollout.DeepCopy()),
+		Rollback:            (*v1alpha1.RollbackStatus)(in.Rollback.DeepCopy()),
+		DeadLetter:          (*v1alpha1.DeadLetterStatus)(in.DeadLetter.DeepCopy()),
+	}
+	for _, target := range in.Traffic {
+		out.Traffic = append(out.Traffic, v1alpha1.TrafficStatus(target))
+	}
+	for _, domain := range in.Domains {
+		out.Domains = append(out.Domains, v1alpha1.DomainStatus(domain))
+	}
+	for _, condition := range in.Conditions {
+		out.Conditions = append(out.Conditions, *condition.DeepCopy())
+	}
+	return out
+}
+
+func statusFromHub(in *v1alpha1.NextAppStatus) NextAppStatus {
+	out := NextAppStatus{
+		URL:                 in.URL,
+		ObservedGeneration:  in.ObservedGeneration,
+		LatestReadyRevision: in.LatestReadyRevision,
+		Rollout:             (*RolloutStatus)(in.Rollout.DeepCopy()),
+		Rollback:            (*RollbackStatus)(in.Rollback.DeepCopy()),
+		DeadLetter:          (*DeadLetterStatus)(in.DeadLetter.DeepCopy()),
+	}
+	for _, target := range in.Traffic {
+		out.Traffic = append(out.Traffic, TrafficStatus(target))
+	}
+	for _, domain := range in.Domains {
+		out.Domains = append(out.Domains, DomainStatus(domain))
+	}
+	for _, condition := range in.Conditions {
+		out.Conditions = append(out.Conditions, *condition.DeepCopy())
+	}
+	return out
+}
+
+func secretName(ref *SecretReference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+func secretReference(name string) *SecretReference {
+	if name == "" {
+		return nil
+	}
+	return &SecretReference{Name: name}
+}
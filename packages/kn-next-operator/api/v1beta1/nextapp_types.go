@@ -0,0 +1,826 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NextAppSpec defines the desired state of NextApp.
+//
+// Compared to v1alpha1, providers are selected by setting exactly one
+// structured sub-object instead of a provider string, Secrets are referenced
+// with objects instead of bare names, and a preview is declared by the
+// presence of spec.preview.
+// +kubebuilder:validation:XValidation:rule="!has(self.deletionPolicy) || self.deletionPolicy != 'Delete' || !has(self.storage) || !has(self.storage.azure)",message="deletionPolicy Delete is not supported with azure storage, which has no per-app prefix",fieldPath=".deletionPolicy"
+type NextAppSpec struct {
+	// The OpenNext bundled Next.js image
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// How many concurrent Next.js pods should be active
+	// +optional
+	Scaling *ScalingSpec `json:"scaling,omitempty"`
+
+	// Object storage bucket for static assets and Server Action uploads
+	// +optional
+	Storage *StorageSpec `json:"storage,omitempty"`
+
+	// Caching infrastructure
+	// +optional
+	Cache *CacheSpec `json:"cache,omitempty"`
+
+	// Revalidation options
+	// +optional
+	Revalidation *RevalidationSpec `json:"revalidation,omitempty"`
+
+	// External Secrets mapping
+	// +optional
+	Secrets *SecretsSpec `json:"secrets,omitempty"`
+
+	// Marks the app as a GitOps preview environment for a pull request
+	// +optional
+	Preview *PreviewSpec `json:"preview,omitempty"`
+
+	// Readiness, liveness and startup probes for the Next.js container
+	// +optional
+	Probes *ProbesSpec `json:"probes,omitempty"`
+
+	// Prometheus scraping and Grafana dashboard provisioning
+	// +optional
+	Observability *ObservabilitySpec `json:"observability,omitempty"`
+
+	// Progressive traffic shifting to new revisions. When unset, Knative
+	// routes all traffic to the latest ready revision.
+	// +optional
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+
+	// Automatic rollback when a new revision fails to become ready
+	// +optional
+	Rollback *RollbackSpec `json:"rollback,omitempty"`
+
+	// Custom domains mapped to the app with Knative DomainMappings
+	// +listType=map
+	// +listMapKey=host
+	// +optional
+	Domains []DomainSpec `json:"domains,omitempty"`
+
+	// What happens to the app's object-storage prefix and Redis cache keys
+	// when the NextApp is deleted. Delete purges them with a Job before the
+	// NextApp is released. Azure storage has no per-app prefix, so Delete is
+	// not supported with it.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// What the operator does when an owned object was edited by hand so it no
+	// longer matches the spec. Correct reverts the edit; Report leaves it in
+	// place and raises the DriftDetected condition.
+	// +kubebuilder:validation:Enum=Correct;Report
+	// +kubebuilder:default=Correct
+	// +optional
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+}
+
+// Deletion policies for the app's storage and cache entries.
+const (
+	DeletionPolicyRetain = "Retain"
+	DeletionPolicyDelete = "Delete"
+)
+
+// Drift policies for hand edits to owned objects.
+const (
+	DriftPolicyCorrect = "Correct"
+	DriftPolicyReport  = "Report"
+)
+
+// SecretReference names a Secret in the NextApp namespace.
+type SecretReference struct {
+	// Name of the Kubernetes Secret resource
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SecretKeyRef points at a single key within a Kubernetes Secret.
+type SecretKeyRef struct {
+	// Name of the Kubernetes Secret resource
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key within the Secret. The default depends on where the reference is
+	// used; for envMap it is the environment variable name.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+type PreviewSpec struct {
+	// Pull request the preview is built from
+	// +kubebuilder:validation:MinLength=1
+	PullRequest string `json:"pullRequest"`
+
+	// +optional
+	Branch string `json:"branch,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.minScale) || !has(self.maxScale) || self.maxScale == 0 || self.minScale <= self.maxScale",message="minScale must not exceed maxScale",fieldPath=".minScale"
+type ScalingSpec struct {
+	// +kubebuilder:validation:Minimum=0
+	MinScale int32 `json:"minScale,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MaxScale int32 `json:"maxScale,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	ContainerConcurrency int32 `json:"containerConcurrency,omitempty"`
+
+	// CPU requested for the Next.js container
+	// +kubebuilder:default="250m"
+	// +optional
+	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+
+	// Memory requested for the Next.js container
+	// +kubebuilder:default="512Mi"
+	// +optional
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+
+	// CPU limit for the Next.js container
+	// +kubebuilder:default="1000m"
+	// +optional
+	CPULimit *resource.Quantity `json:"cpuLimit,omitempty"`
+
+	// Memory limit for the Next.js container
+	// +kubebuilder:default="1Gi"
+	// +optional
+	MemoryLimit *resource.Quantity `json:"memoryLimit,omitempty"`
+}
+
+type ProbesSpec struct {
+	// Readiness probe, defaults to /api/health on port 3000
+	// +optional
+	Readiness *ProbeSpec `json:"readiness,omitempty"`
+
+	// Liveness probe, defaults to /api/health on port 3000
+	// +optional
+	Liveness *ProbeSpec `json:"liveness,omitempty"`
+
+	// Startup probe, only added when set. Useful for slow-booting bytecode binaries.
+	// +optional
+	Startup *ProbeSpec `json:"startup,omitempty"`
+}
+
+// ProbeSpec configures an HTTP GET probe. Unset fields fall back to the operator
+// defaults; the timings are pointers so an explicit 0 is kept.
+type ProbeSpec struct {
+	// HTTP path to probe
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Container port to probe. Knative probes the user container on its
+	// serving port, so only the app's port 3000 is accepted.
+	// +kubebuilder:validation:XValidation:rule="self == 3000",message="must be the app container port 3000"
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+type RolloutSpec struct {
+	// Traffic steps applied to a new revision once it is ready. After the
+	// last step the revision is promoted to 100%.
+	// +kubebuilder:validation:MinItems=1
+	Steps []RolloutStep `json:"steps"`
+}
+
+type RolloutStep struct {
+	// Percentage of traffic sent to the new revision during this step
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// How long to hold this step before moving on, e.g. 5m
+	// +optional
+	Pause metav1.Duration `json:"pause,omitempty"`
+}
+
+type DomainSpec struct {
+	// Fully qualified host name, e.g. app.example.com
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Host string `json:"host"`
+
+	// Next.js i18n locale served on this domain, matching the defaultLocale
+	// of the corresponding entry in i18n.domains
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// TLS termination for the domain
+	// +optional
+	TLS *DomainTLSSpec `json:"tls,omitempty"`
+}
+
+// DomainTLSSpec configures the certificate used for a custom domain. With an
+// issuerRef, a cert-manager Certificate is created into secretName; without
+// one, secretName must already hold the certificate.
+type DomainTLSSpec struct {
+	// Secret holding the TLS certificate, defaults to <host>-tls
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// cert-manager issuer to request the certificate from
+	// +optional
+	IssuerRef *IssuerRef `json:"issuerRef,omitempty"`
+}
+
+type IssuerRef struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+type RollbackSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// How long a new revision may take to become ready before traffic is
+	// pinned back to the last good revision
+	// +kubebuilder:default="10m"
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+type ObservabilitySpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Path the Next.js server exposes Prometheus metrics on
+	// +kubebuilder:default="/metrics"
+	// +optional
+	MetricsPath string `json:"metricsPath,omitempty"`
+
+	// +optional
+	Prometheus *PrometheusSpec `json:"prometheus,omitempty"`
+
+	// +optional
+	Grafana *GrafanaSpec `json:"grafana,omitempty"`
+
+	// PrometheusRule alerting for the app
+	// +optional
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
+}
+
+type PrometheusSpec struct {
+	// +kubebuilder:default="15s"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+}
+
+// AlertsSpec configures the generated PrometheusRule. Unset thresholds use the defaults.
+type AlertsSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Fire when the share of 5xx responses exceeds this percentage
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=5
+	// +optional
+	ErrorRatePercent int32 `json:"errorRatePercent,omitempty"`
+
+	// Fire when p95 request latency exceeds this many milliseconds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=2000
+	// +optional
+	LatencyP95Milliseconds int32 `json:"latencyP95Milliseconds,omitempty"`
+
+	// Fire when p95 cold-start duration exceeds this many seconds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// +optional
+	ColdStartP95Seconds int32 `json:"coldStartP95Seconds,omitempty"`
+
+	// Fire when the revalidation consumer group lags by more than this many messages
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1000
+	// +optional
+	RevalidationLagMessages int32 `json:"revalidationLagMessages,omitempty"`
+
+	// How long a condition must hold before an alert fires
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	For string `json:"for,omitempty"`
+}
+
+type GrafanaSpec struct {
+	// Deploy the dashboard ConfigMap, defaults to true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// StorageSpec selects the object storage provider by setting exactly one of
+// its sub-objects.
+// +kubebuilder:validation:XValidation:rule="[has(self.gcs), has(self.s3), has(self.azure), has(self.minio)].exists_one(p, p)",message="exactly one of gcs, s3, azure or minio must be set"
+type StorageSpec struct {
+	// +optional
+	GCS *GCSStorageSpec `json:"gcs,omitempty"`
+
+	// +optional
+	S3 *S3StorageSpec `json:"s3,omitempty"`
+
+	// +optional
+	Azure *AzureStorageSpec `json:"azure,omitempty"`
+
+	// +optional
+	MinIO *MinIOStorageSpec `json:"minio,omitempty"`
+
+	// Public CDN URL static assets are served from, exposed to the app as ASSET_PREFIX
+	// +optional
+	PublicURL string `json:"publicUrl,omitempty"`
+}
+
+type GCSStorageSpec struct {
+	Bucket string `json:"bucket"`
+
+	// Secret with the service account JSON key in key.json. Leave unset to
+	// rely on workload identity.
+	// +optional
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+type S3StorageSpec struct {
+	Bucket string `json:"bucket"`
+
+	// Bucket region, defaults to us-east-1
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Custom endpoint for S3-compatible storage
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Secret with accessKey/secretKey. Leave unset to rely on IAM.
+	// +optional
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+type AzureStorageSpec struct {
+	// Blob container name
+	Container string `json:"container"`
+
+	// Custom Blob service endpoint
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Secret with the storage account connection string in connectionString.
+	// Leave unset to rely on workload identity.
+	// +optional
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+type MinIOStorageSpec struct {
+	Bucket string `json:"bucket"`
+
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+
+	// Bucket region, defaults to us-east-1
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Secret with accessKey/secretKey
+	// +optional
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.incremental) || !has(self.incremental.dynamodb)",message="dynamodb can only back the tag cache"
+// +kubebuilder:validation:XValidation:rule="!has(self.tag) || has(self.tag.redis) || has(self.tag.dynamodb)",message="the tag cache must use redis or dynamodb"
+type CacheSpec struct {
+	// Backend for the ISR/fetch incremental cache
+	// +optional
+	Incremental *CacheBackendSpec `json:"incremental,omitempty"`
+
+	// Backend for the revalidation tag cache
+	// +optional
+	Tag *CacheBackendSpec `json:"tag,omitempty"`
+
+	// Shared V8 bytecode cache volume, provisioned when set
+	// +optional
+	Bytecode *BytecodeCacheSpec `json:"bytecode,omitempty"`
+}
+
+// CacheBackendSpec selects a cache provider by setting exactly one of its
+// sub-objects. The gcs and s3 providers reuse the bucket configured in
+// spec.storage.
+// +kubebuilder:validation:XValidation:rule="[has(self.redis), has(self.dynamodb), has(self.gcs), has(self.s3)].exists_one(p, p)",message="exactly one of redis, dynamodb, gcs or s3 must be set"
+type CacheBackendSpec struct {
+	// +optional
+	Redis *RedisCacheSpec `json:"redis,omitempty"`
+
+	// +optional
+	DynamoDB *DynamoDBCacheSpec `json:"dynamodb,omitempty"`
+
+	// +optional
+	GCS *BucketCacheSpec `json:"gcs,omitempty"`
+
+	// +optional
+	S3 *BucketCacheSpec `json:"s3,omitempty"`
+}
+
+// BucketCacheSpec stores cache entries in the spec.storage bucket.
+type BucketCacheSpec struct{}
+
+// +kubebuilder:validation:XValidation:rule="has(self.url) != has(self.urlSecretRef)",message="exactly one of url or urlSecretRef must be set"
+type RedisCacheSpec struct {
+	// Redis connection URL. Prefer urlSecretRef when it embeds credentials.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Secret key holding the full Redis connection URL, key defaults to url
+	// +optional
+	URLSecretRef *SecretKeyRef `json:"urlSecretRef,omitempty"`
+
+	// Secret key holding the Redis password, spliced into url at runtime.
+	// The key defaults to password and the value must be URL-safe.
+	// +optional
+	PasswordSecretRef *SecretKeyRef `json:"passwordSecretRef,omitempty"`
+
+	// Prefix for every cache key, defaults to <namespace>/<name> so several
+	// NextApps can share one Redis
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// +optional
+	TLS *RedisTLSSpec `json:"tls,omitempty"`
+}
+
+type RedisTLSSpec struct {
+	// Connect over TLS (rediss://)
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Secret key holding the PEM CA bundle used to verify the Redis server.
+	// The key defaults to ca.crt.
+	// +optional
+	CASecretRef *SecretKeyRef `json:"caSecretRef,omitempty"`
+}
+
+type DynamoDBCacheSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	TableName string `json:"tableName"`
+
+	// +kubebuilder:validation:MinLength=1
+	Region string `json:"region"`
+
+	// Secret with accessKey/secretKey. Leave unset to rely on IAM.
+	// +optional
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+type BytecodeCacheSpec struct {
+	// Size of the cache PVC, defaults to 512Mi
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// RevalidationSpec selects the queue by setting at most one of kafka or
+// broker.
+// +kubebuilder:validation:XValidation:rule="!(has(self.kafka) && has(self.broker))",message="kafka and broker are mutually exclusive"
+type RevalidationSpec struct {
+	// Publish revalidations to a Kafka topic consumed by a KafkaSource
+	// +optional
+	Kafka *KafkaQueueSpec `json:"kafka,omitempty"`
+
+	// Post revalidations as CloudEvents to a Knative Eventing Broker
+	// +optional
+	Broker *BrokerQueueSpec `json:"broker,omitempty"`
+
+	// Sink that receives revalidation events from the queue
+	// +optional
+	Revalidator *RevalidatorSpec `json:"revalidator,omitempty"`
+
+	// Retry and dead-letter policy for delivering revalidation events
+	// +optional
+	Delivery *DeliverySpec `json:"delivery,omitempty"`
+}
+
+type KafkaQueueSpec struct {
+	// Comma-separated list of bootstrap servers
+	// +kubebuilder:validation:MinLength=1
+	BootstrapServers string `json:"bootstrapServers"`
+
+	// Kafka topic revalidation events are published to
+	// +optional
+	Topic *KafkaTopicSpec `json:"topic,omitempty"`
+
+	// SASL and TLS settings for brokers that require authentication
+	// +optional
+	Auth *KafkaAuthSpec `json:"auth,omitempty"`
+}
+
+type BrokerQueueSpec struct {
+	// Name of the Broker in the NextApp namespace
+	// +kubebuilder:default=default
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+type DeliverySpec struct {
+	// Number of retries before an event is dead-lettered
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retry *int32 `json:"retry,omitempty"`
+
+	// +kubebuilder:validation:Enum=linear;exponential
+	// +optional
+	BackoffPolicy string `json:"backoffPolicy,omitempty"`
+
+	// Delay before the first retry, e.g. 200ms
+	// +optional
+	BackoffDelay *metav1.Duration `json:"backoffDelay,omitempty"`
+
+	// Where events that exhausted their retries are sent. Defaults to the
+	// operator's dead-letter receiver, which counts them in
+	// status.deadLetter.
+	// +optional
+	DeadLetterSinkURI string `json:"deadLetterSinkUri,omitempty"`
+}
+
+type KafkaAuthSpec struct {
+	// +optional
+	SASL *KafkaSASLSpec `json:"sasl,omitempty"`
+
+	// +optional
+	TLS *KafkaTLSSpec `json:"tls,omitempty"`
+}
+
+type KafkaSASLSpec struct {
+	// Secret with the user, password and saslType (PLAIN, SCRAM-SHA-256 or
+	// SCRAM-SHA-512) keys
+	CredentialsSecretRef SecretReference `json:"credentialsSecretRef"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.certSecretRef) == has(self.keySecretRef)",message="certSecretRef and keySecretRef must be set together"
+type KafkaTLSSpec struct {
+	// Connect to the brokers over TLS (SSL or SASL_SSL)
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Secret key holding the PEM CA bundle used to verify the brokers.
+	// The key defaults to ca.crt.
+	// +optional
+	CASecretRef *SecretKeyRef `json:"caSecretRef,omitempty"`
+
+	// Client certificate for mutual TLS. The key defaults to tls.crt.
+	// +optional
+	CertSecretRef *SecretKeyRef `json:"certSecretRef,omitempty"`
+
+	// Client private key for mutual TLS. The key defaults to tls.key.
+	// +optional
+	KeySecretRef *SecretKeyRef `json:"keySecretRef,omitempty"`
+}
+
+type KafkaTopicSpec struct {
+	// Topic name, defaults to <app>-isr-revalidation like the TypeScript
+	// KafkaQueueConfig
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]+$`
+	// +kubebuilder:validation:MaxLength=249
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Partitions int32 `json:"partitions,omitempty"`
+
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
+
+	// How long messages are kept, e.g. 24h. Defaults to the broker setting.
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
+
+	// Provision the topic as a Strimzi KafkaTopic
+	// +optional
+	Strimzi *StrimziTopicSpec `json:"strimzi,omitempty"`
+}
+
+type StrimziTopicSpec struct {
+	// Name of the Strimzi Kafka cluster, set as the strimzi.io/cluster label
+	// +kubebuilder:validation:MinLength=1
+	Cluster string `json:"cluster"`
+
+	// Namespace watched by the Strimzi topic operator, defaults to the
+	// NextApp namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RevalidatorSpec selects where revalidation events are delivered. With an
+// image, a dedicated <app>-revalidator Knative Service is run that calls back
+// into the app; without one, the app itself receives the events on path.
+type RevalidatorSpec struct {
+	// Image of a dedicated revalidator service
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Route on the app that handles revalidation CloudEvents, used when no
+	// image is set
+	// +kubebuilder:default="/api/revalidate"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+type SecretsSpec struct {
+	// Secrets whose keys are all exposed as environment variables
+	// +optional
+	EnvFrom []SecretReference `json:"envFrom,omitempty"`
+
+	// Map of explicit ENV_VAR -> Secret key, injected via valueFrom.secretKeyRef
+	// +optional
+	EnvMap map[string]SecretKeyRef `json:"envMap,omitempty"`
+}
+
+// NextAppStatus defines the observed state of NextApp.
+type NextAppStatus struct {
+	URL string `json:"url,omitempty"`
+
+	// Generation of the spec the status was last computed from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Latest revision of the Knative Service that became ready
+	// +optional
+	LatestReadyRevision string `json:"latestReadyRevision,omitempty"`
+
+	// Traffic split currently served by the Knative Service route
+	// +optional
+	Traffic []TrafficStatus `json:"traffic,omitempty"`
+
+	// Progress of the current traffic rollout
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// The failed revision traffic was rolled back from, if any
+	// +optional
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+
+	// Readiness of each custom domain
+	// +optional
+	Domains []DomainStatus `json:"domains,omitempty"`
+
+	// Revalidation events counted by the operator's dead-letter receiver
+	// +optional
+	DeadLetter *DeadLetterStatus `json:"deadLetter,omitempty"`
+
+	// conditions represent the current state of the NextApp resource.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type RolloutStatus struct {
+	// Revision currently receiving the remaining traffic
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// Revision being rolled out, empty when no rollout is in progress
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+
+	// Index into spec.rollout.steps of the current step
+	CurrentStep int32 `json:"currentStep,omitempty"`
+
+	// Traffic percentage currently sent to the canary revision
+	CanaryPercent int32 `json:"canaryPercent,omitempty"`
+
+	// When the current step started
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+}
+
+type TrafficStatus struct {
+	// Revision receiving this share of traffic
+	RevisionName string `json:"revisionName,omitempty"`
+
+	// Tag addressing the revision directly, if any
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// Whether the target follows the latest ready revision
+	// +optional
+	LatestRevision bool `json:"latestRevision,omitempty"`
+
+	// Percentage of requests routed to the revision
+	Percent int64 `json:"percent"`
+
+	// URL of the tagged revision
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+type DomainStatus struct {
+	Host   string `json:"host"`
+	Locale string `json:"locale,omitempty"`
+	URL    string `json:"url,omitempty"`
+
+	// Ready condition of the DomainMapping: True, False or Unknown
+	Ready metav1.ConditionStatus `json:"ready"`
+
+	// Why the domain is not ready yet
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type DeadLetterStatus struct {
+	// Total number of dead-lettered revalidation events
+	Events int64 `json:"events"`
+
+	// When the last event was dead-lettered
+	// +optional
+	LastEventTime *metav1.Time `json:"lastEventTime,omitempty"`
+}
+
+type RollbackStatus struct {
+	// Latest created revision that failed to become ready
+	FailedRevision string `json:"failedRevision"`
+
+	// Image of the failed revision
+	FailedImage string `json:"failedImage,omitempty"`
+
+	// Last good revision traffic is pinned to
+	TargetRevision string `json:"targetRevision,omitempty"`
+
+	// When the rollback happened
+	Time metav1.Time `json:"time"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`,priority=1
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.status.latestReadyRevision`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NextApp is the Schema for the nextapps API
+type NextApp struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NextApp
+	// +required
+	Spec NextAppSpec `json:"spec"`
+
+	// status defines the observed state of NextApp
+	// +optional
+	Status NextAppStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NextAppList contains a list of NextApp
+type NextAppList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NextApp `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NextApp{}, &NextAppList{})
+}
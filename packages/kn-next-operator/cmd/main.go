@@ -25,10 +25,14 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -36,8 +40,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+	appsv1beta1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1beta1"
 	"github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/internal/controller"
+	"github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/internal/deadletter"
+	webhookv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/internal/webhook/v1alpha1"
+	webhookv1beta1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/internal/webhook/v1beta1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -50,7 +59,9 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(appsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(appsv1beta1.AddToScheme(scheme))
 	utilruntime.Must(servingv1.AddToScheme(scheme))
+	utilruntime.Must(servingv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -61,12 +72,17 @@ func main() {
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
+	var deadLetterAddr, deadLetterURL string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&deadLetterAddr, "dead-letter-bind-address", "0", "The address the revalidation dead-letter "+
+		"receiver binds to, or 0 to disable it.")
+	flag.StringVar(&deadLetterURL, "dead-letter-url", "", "The in-cluster URL of the dead-letter receiver, "+
+		"used as the default dead-letter sink for revalidation events.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -163,6 +179,13 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "2dd0b3e2.kn-next.dev",
+		// Purge Jobs and metrics Services are the only Jobs and Services the operator reads
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&batchv1.Job{}:    {Label: controller.NextAppSelector()},
+				&corev1.Service{}: {Label: controller.NextAppSelector()},
+			},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -180,13 +203,36 @@ func main() {
 		os.Exit(1)
 	}
 
+	var deadLetters *deadletter.Receiver
+	if deadLetterAddr != "0" && deadLetterURL != "" {
+		deadLetters = deadletter.NewReceiver(deadLetterAddr, deadLetterURL,
+			mgr.GetClient(), mgr.GetAPIReader(), mgr.GetEventRecorder("dead-letter-receiver"))
+		if err := mgr.Add(deadLetters); err != nil {
+			setupLog.Error(err, "Failed to add dead-letter receiver")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&controller.NextAppReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Recorder:    mgr.GetEventRecorder("nextapp-controller"),
+		DeadLetters: deadLetters,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Failed to create controller", "controller", "NextApp")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupNextAppWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "Failed to create webhook", "webhook", "NextApp")
+			os.Exit(1)
+		}
+		if err := webhookv1beta1.SetupNextAppWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "Failed to create webhook", "webhook", "NextApp")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
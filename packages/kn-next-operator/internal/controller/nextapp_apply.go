@@ -0,0 +1,276 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// fieldManager owns the fields the operator server-side applies.
+const fieldManager = "kn-next-operator"
+
+// legacyFieldManagers wrote owned objects with Update before the operator
+// switched to server-side apply; "manager" is the operator binary's name.
+// Their ownership is handed to fieldManager so spec changes don't conflict
+// with the operator's own earlier writes.
+var legacyFieldManagers = sets.New("manager")
+
+// appliedAnnotation marks objects the operator has server-side applied. Other
+// kubebuilder operators also run as "manager", so legacyFieldManagers are only
+// upgraded on objects without it: once the operator's own Update writes are
+// handed over, later ones are another controller's and are left alone.
+const appliedAnnotation = "apps.kn-next.dev/server-side-applied"
+
+// apply server-side applies the desired state in obj under fieldManager and
+// fills obj with the live object. obj must hold every field the operator
+// manages: fields it applied before and leaves out now are removed, fields
+// set by other managers are kept. Ownership is not forced, so a field another
+// manager owns with a different value fails with a Conflict that is reported
+// as a Warning Event instead of being overwritten.
+//
+// Fields edited by hand since the last apply are recorded in drift and
+// handled per spec.driftPolicy: Correct takes them back from the managers
+// that edited them and applies them again, Report leaves them out of the
+// apply.
+func (r *NextAppReconciler) apply(ctx context.Context, nextApp *appsv1alpha1.NextApp, drift *driftReport, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	if _, ok := obj.(*unstructured.Unstructured); !ok {
+		omitZeroFields(reflect.ValueOf(obj), u.Object)
+	}
+	u.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(u.Object, "status")
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[appliedAnnotation] = "true"
+	u.SetAnnotations(annotations)
+
+	live, err := r.liveObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if live != nil {
+		fields, err := driftedFields(u, live)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			drift.add(gvk.Kind, obj.GetName(), fields)
+			if nextApp.Spec.DriftPolicy == appsv1alpha1.DriftPolicyReport {
+				for _, path := range fields {
+					removeField(u.Object, path)
+				}
+				r.applyEvent(nextApp, live, corev1.EventTypeWarning, "DriftDetected",
+					"%s %s was edited by hand, left in place: %s", gvk.Kind, obj.GetName(), fieldList(fields))
+			} else {
+				patch, err := releaseFields(live, fields)
+				if err != nil {
+					return err
+				}
+				live.SetGroupVersionKind(gvk)
+				if err := r.Patch(ctx, live, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+					return fmt.Errorf("release drifted fields of %s %s: %w", gvk.Kind, obj.GetName(), err)
+				}
+				r.applyEvent(nextApp, live, corev1.EventTypeWarning, "DriftDetected",
+					"%s %s was edited by hand, reverted: %s", gvk.Kind, obj.GetName(), fieldList(fields))
+			}
+		}
+	}
+
+	err = r.Apply(ctx, client.ApplyConfigurationFromUnstructured(u), client.FieldOwner(fieldManager))
+	switch {
+	case errors.IsConflict(err):
+		r.applyEvent(nextApp, nil, corev1.EventTypeWarning, "ApplyConflict",
+			"%s %s has fields owned by another field manager: %v", gvk.Kind, obj.GetName(), err)
+	case err != nil:
+		r.applyEvent(nextApp, nil, corev1.EventTypeWarning, "ApplyFailed",
+			"Failed to apply %s %s: %v", gvk.Kind, obj.GetName(), err)
+	case live == nil:
+		r.applyEvent(nextApp, u, corev1.EventTypeNormal, "Created", "Created %s %s", gvk.Kind, obj.GetName())
+	case live.GetResourceVersion() != u.GetResourceVersion():
+		r.applyEvent(nextApp, u, corev1.EventTypeNormal, "Updated", "Updated %s %s", gvk.Kind, obj.GetName())
+	}
+	if err != nil {
+		return fmt.Errorf("apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj)
+}
+
+// liveObject reads the stored obj as unstructured, or nil when it does not
+// exist yet. On objects the operator has not applied yet, fields
+// legacyFieldManagers own are moved to fieldManager first, so the operator's
+// own earlier writes are not taken for drift.
+func (r *NextAppReconciler) liveObject(ctx context.Context, obj client.Object) (*unstructured.Unstructured, error) {
+	live, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", obj)
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, live)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := live.GetAnnotations()[appliedAnnotation]; ok {
+		return toUnstructured(live)
+	}
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(live, legacyFieldManagers, fieldManager)
+	if err != nil {
+		return nil, err
+	}
+	if patch != nil {
+		if err := r.Patch(ctx, live, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+			return nil, fmt.Errorf("upgrade managed fields of %s: %w", obj.GetName(), err)
+		}
+	}
+	return toUnstructured(live)
+}
+
+func toUnstructured(obj client.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+var marshalerType = reflect.TypeFor[json.Marshaler]()
+
+// omitZeroFields removes the zero values the typed structs cannot omit from
+// obj, the unstructured form of v, so the apply only claims the fields the
+// operator sets. Pointers that are set are kept even when they point to an
+// empty struct, such as an emptyDir volume source.
+func omitZeroFields(v reflect.Value, obj map[string]interface{}) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if strings.Contains(options, "inline") || (field.Anonymous && name == "") {
+			omitZeroFields(fv, obj)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		item, ok := obj[name]
+		if !ok {
+			continue
+		}
+		m, isMap := item.(map[string]interface{})
+		switch fv.Kind() {
+		case reflect.Pointer:
+			if fv.IsNil() {
+				delete(obj, name)
+			} else if isMap && !opaque(fv.Type()) {
+				omitZeroFields(fv, m)
+			}
+		case reflect.Struct:
+			if isMap && !opaque(fv.Type()) {
+				omitZeroFields(fv, m)
+				if len(m) == 0 {
+					delete(obj, name)
+				}
+			} else if fv.IsZero() {
+				delete(obj, name)
+			}
+		case reflect.Slice:
+			if fv.IsNil() {
+				delete(obj, name)
+				continue
+			}
+			list, ok := item.([]interface{})
+			if !ok || len(list) != fv.Len() {
+				continue
+			}
+			for j := range list {
+				if m, ok := list[j].(map[string]interface{}); ok && !opaque(fv.Type().Elem()) {
+					omitZeroFields(fv.Index(j), m)
+				}
+			}
+		case reflect.Map:
+			if fv.IsNil() {
+				delete(obj, name)
+			}
+		default:
+			if fv.IsZero() {
+				delete(obj, name)
+			}
+		}
+	}
+}
+
+// opaque reports whether t marshals itself, like metav1.Time or
+// resource.Quantity, so its fields do not map to unstructured ones.
+func opaque(t reflect.Type) bool {
+	return t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType)
+}
+
+// applyEvent records the outcome of applying an owned object. Its reasons
+// and notes leave out resource versions, so repeats of the same outcome are
+// counted in one Event series rather than recorded anew.
+func (r *NextAppReconciler) applyEvent(nextApp *appsv1alpha1.NextApp, related runtime.Object, eventType, reason, note string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(nextApp, related, eventType, reason, "Apply", note, args...)
+	}
+}
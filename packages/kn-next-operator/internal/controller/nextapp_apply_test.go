@@ -0,0 +1,197 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("apply", func() {
+	var (
+		reconciler *NextAppReconciler
+		recorder   *events.FakeRecorder
+		nextApp    *appsv1alpha1.NextApp
+		drift      *driftReport
+	)
+
+	serviceAccount := func() *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{
+			ObjectMeta:                   metav1.ObjectMeta{Name: "shop-sa", Namespace: "store"},
+			AutomountServiceAccountToken: ptr.To(false),
+		}
+	}
+	handEdit := func() {
+		sa := &corev1.ServiceAccount{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "shop-sa", Namespace: "store"}, sa)).To(Succeed())
+		sa.AutomountServiceAccountToken = ptr.To(true)
+		Expect(reconciler.Update(ctx, sa, client.FieldOwner("kubectl-edit"))).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		recorder = events.NewFakeRecorder(10)
+		reconciler = &NextAppReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithReturnManagedFields().Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+		nextApp = &appsv1alpha1.NextApp{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "store"}}
+		drift = &driftReport{}
+		Expect(reconciler.apply(ctx, nextApp, drift, serviceAccount())).To(Succeed())
+	})
+
+	It("should record creations and changes", func() {
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Created ServiceAccount shop-sa")))
+
+		sa := serviceAccount()
+		sa.Labels = map[string]string{"app": "shop"}
+		Expect(reconciler.apply(ctx, nextApp, drift, sa)).To(Succeed())
+		Expect(recorder.Events).To(Receive(Equal("Normal Updated Updated ServiceAccount shop-sa")))
+	})
+
+	It("should revert a hand edit with the Correct drift policy", func() {
+		handEdit()
+		sa := serviceAccount()
+		Expect(reconciler.apply(ctx, nextApp, drift, sa)).To(Succeed())
+		Expect(sa.AutomountServiceAccountToken).To(HaveValue(BeFalse()))
+		Expect(drift.String()).To(Equal("ServiceAccount shop-sa (automountServiceAccountToken)"))
+
+		Expect(recorder.Events).To(Receive())
+		Expect(recorder.Events).To(Receive(Equal(
+			"Warning DriftDetected ServiceAccount shop-sa was edited by hand, reverted: automountServiceAccountToken")))
+	})
+
+	It("should not force fields another server-side applier owns", func() {
+		other := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion":                   "v1",
+			"kind":                         "ServiceAccount",
+			"metadata":                     map[string]interface{}{"name": "shop-sa", "namespace": "store"},
+			"automountServiceAccountToken": true,
+		}}
+		Expect(reconciler.Apply(ctx, client.ApplyConfigurationFromUnstructured(other),
+			client.FieldOwner("other-tool"), client.ForceOwnership)).To(Succeed())
+
+		sa := serviceAccount()
+		Expect(errors.IsConflict(reconciler.apply(ctx, nextApp, drift, sa))).To(BeTrue())
+		Expect(drift.String()).To(BeEmpty())
+	})
+
+	It("should leave a hand edit in place with the Report drift policy", func() {
+		nextApp.Spec.DriftPolicy = appsv1alpha1.DriftPolicyReport
+		handEdit()
+		sa := serviceAccount()
+		sa.Labels = map[string]string{"app": "shop"}
+		Expect(reconciler.apply(ctx, nextApp, drift, sa)).To(Succeed())
+		Expect(sa.AutomountServiceAccountToken).To(HaveValue(BeTrue()))
+		Expect(sa.Labels).To(HaveKeyWithValue("app", "shop"))
+		Expect(drift.String()).To(Equal("ServiceAccount shop-sa (automountServiceAccountToken)"))
+	})
+})
+
+var _ = Describe("legacy field manager upgrade", func() {
+	var (
+		reconciler *NextAppReconciler
+		nextApp    *appsv1alpha1.NextApp
+		drift      *driftReport
+	)
+
+	// createdBy writes the ServiceAccount with Update as the given manager
+	createdBy := func(manager string, annotations map[string]string) {
+		sa := &corev1.ServiceAccount{
+			ObjectMeta:                   metav1.ObjectMeta{Name: "shop-sa", Namespace: "store", Annotations: annotations},
+			AutomountServiceAccountToken: ptr.To(true),
+		}
+		Expect(reconciler.Create(ctx, sa, client.FieldOwner(manager))).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		reconciler = &NextAppReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithReturnManagedFields().Build(),
+			Scheme: scheme,
+		}
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "store"},
+			Spec:       appsv1alpha1.NextAppSpec{DriftPolicy: appsv1alpha1.DriftPolicyReport},
+		}
+		drift = &driftReport{}
+	})
+
+	It("should take over the operator's own writes from before server-side apply", func() {
+		createdBy("manager", nil)
+		sa := appServiceAccount(nextApp)
+		Expect(reconciler.apply(ctx, nextApp, drift, sa)).To(Succeed())
+		Expect(drift.String()).To(BeEmpty())
+		Expect(sa.AutomountServiceAccountToken).To(HaveValue(BeFalse()))
+		Expect(sa.Annotations).To(HaveKey(appliedAnnotation))
+	})
+
+	It("should leave writes of another manager alone once the object was applied", func() {
+		createdBy("manager", map[string]string{appliedAnnotation: "true"})
+		sa := appServiceAccount(nextApp)
+		Expect(reconciler.apply(ctx, nextApp, drift, sa)).To(Succeed())
+		Expect(drift.String()).To(Equal("ServiceAccount shop-sa (automountServiceAccountToken)"))
+		Expect(sa.AutomountServiceAccountToken).To(HaveValue(BeTrue()))
+	})
+})
+
+var _ = Describe("omitZeroFields", func() {
+	It("should drop the zero values and keep the pointers that are set", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Image: "shop:1"}},
+				Volumes: []corev1.Volume{{
+					Name:         "cache",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				}},
+				AutomountServiceAccountToken: ptr.To(false),
+			},
+		}
+		u, err := toUnstructured(pod)
+		Expect(err).NotTo(HaveOccurred())
+		omitZeroFields(reflect.ValueOf(pod), u.Object)
+		Expect(u.Object).To(Equal(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "shop"},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"image": "shop:1"}},
+				"volumes": []interface{}{map[string]interface{}{
+					"name":     "cache",
+					"emptyDir": map[string]interface{}{},
+				}},
+				"automountServiceAccountToken": false,
+			},
+		}))
+	})
+})
@@ -0,0 +1,220 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// resolveCacheBackends returns the effective incremental and tag cache
+// backends, expanding the legacy provider/url Redis shorthand for whichever
+// one is not configured explicitly.
+func resolveCacheBackends(cache *appsv1alpha1.CacheSpec) (incremental, tag *appsv1alpha1.CacheBackendSpec) {
+	if cache == nil {
+		return nil, nil
+	}
+	incremental, tag = cache.IncrementalCache, cache.TagCache
+	if cache.Provider == appsv1alpha1.CacheProviderRedis && cache.URL != "" {
+		legacy := &appsv1alpha1.CacheBackendSpec{
+			Provider: appsv1alpha1.CacheProviderRedis,
+			Redis:    &appsv1alpha1.RedisCacheSpec{URL: cache.URL},
+		}
+		if incremental == nil {
+			incremental = legacy
+		}
+		if tag == nil {
+			tag = legacy
+		}
+	}
+	return incremental, tag
+}
+
+const redisCAMountPath = "/var/secrets/redis-tls"
+
+// cacheWiring returns the env contract for the incremental and tag cache
+// adapters. It rejects combinations the adapters cannot serve, such as an
+// object-storage incremental cache without a matching spec.storage bucket.
+func cacheWiring(nextApp *appsv1alpha1.NextApp) (podWiring, error) {
+	var w podWiring
+	cache, storage := nextApp.Spec.Cache, nextApp.Spec.Storage
+	if cache == nil {
+		return w, nil
+	}
+	if cache.EnableBytecodeCache {
+		w.addEnv("NODE_COMPILE_CACHE", "/cache/bytecode/latest")
+	}
+
+	incremental, tag := resolveCacheBackends(cache)
+	var redis *appsv1alpha1.RedisCacheSpec
+
+	if incremental != nil {
+		switch incremental.Provider {
+		case appsv1alpha1.CacheProviderRedis:
+			if err := validateRedis(incremental.Redis); err != nil {
+				return w, fmt.Errorf("cache.incrementalCache: %w", err)
+			}
+			redis = incremental.Redis
+		case appsv1alpha1.CacheProviderGCS:
+			if storage == nil || storage.Provider != appsv1alpha1.StorageProviderGCS {
+				return w, fmt.Errorf("cache.incrementalCache: the gcs provider requires storage.provider gcs")
+			}
+		case appsv1alpha1.CacheProviderS3:
+			if storage == nil || (storage.Provider != appsv1alpha1.StorageProviderS3 &&
+				storage.Provider != appsv1alpha1.StorageProviderMinio) {
+				return w, fmt.Errorf("cache.incrementalCache: the s3 provider requires storage.provider s3 or minio")
+			}
+		default:
+			return w, fmt.Errorf("cache.incrementalCache: unsupported provider %q", incremental.Provider)
+		}
+	}
+
+	if tag != nil {
+		switch tag.Provider {
+		case appsv1alpha1.CacheProviderRedis:
+			if err := validateRedis(tag.Redis); err != nil {
+				return w, fmt.Errorf("cache.tagCache: %w", err)
+			}
+			// Both Redis adapters read the same REDIS_* env, so they must share one configuration
+			if redis != nil && !equality.Semantic.DeepEqual(redis, tag.Redis) {
+				return w, fmt.Errorf("cache.incrementalCache and cache.tagCache must use the same Redis settings")
+			}
+			redis = tag.Redis
+		case appsv1alpha1.CacheProviderDynamoDB:
+			ddb := tag.DynamoDB
+			if ddb == nil || ddb.TableName == "" || ddb.Region == "" {
+				return w, fmt.Errorf("cache.tagCache: dynamodb.tableName and dynamodb.region are required for the dynamodb provider")
+			}
+			if err := validateDynamoDB(ddb, storage); err != nil {
+				return w, fmt.Errorf("cache.tagCache: %w", err)
+			}
+			w.addEnv("CACHE_DYNAMO_TABLE", ddb.TableName)
+			w.addEnv("CACHE_BUCKET_REGION", ddb.Region)
+			if ddb.CredentialsSecret != "" {
+				w.addSecretEnv("AWS_ACCESS_KEY_ID", ddb.CredentialsSecret, appsv1alpha1.StorageCredentialsAccessKey)
+				w.addSecretEnv("AWS_SECRET_ACCESS_KEY", ddb.CredentialsSecret, appsv1alpha1.StorageCredentialsSecretKey)
+			}
+		default:
+			return w, fmt.Errorf("cache.tagCache: unsupported provider %q", tag.Provider)
+		}
+	}
+
+	if redis != nil {
+		w.merge(redisWiring(redis, nextApp.Namespace+"/"+nextApp.Name))
+	}
+	return w, nil
+}
+
+// validateDynamoDB rejects a DynamoDB tag cache that would override the S3
+// bucket's env. The OpenNext DynamoDB adapter reads CACHE_BUCKET_REGION and
+// the AWS SDK the AWS_* credentials, the same names the S3 adapters use.
+func validateDynamoDB(ddb *appsv1alpha1.DynamoDBCacheSpec, storage *appsv1alpha1.StorageSpec) error {
+	if storage == nil || (storage.Provider != appsv1alpha1.StorageProviderS3 &&
+		storage.Provider != appsv1alpha1.StorageProviderMinio) {
+		return nil
+	}
+	region := storage.Region
+	if region == "" {
+		region = defaultStorageRegion
+	}
+	if ddb.Region != region {
+		return fmt.Errorf("dynamodb.region %q must match the storage region %q, both are read from CACHE_BUCKET_REGION", ddb.Region, region)
+	}
+	if ddb.CredentialsSecret != "" && ddb.CredentialsSecret != storage.CredentialsSecret {
+		return fmt.Errorf("dynamodb.credentialsSecret must match storage.credentialsSecret, both are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return nil
+}
+
+func validateRedis(redis *appsv1alpha1.RedisCacheSpec) error {
+	if redis == nil || (redis.URL == "" && redis.URLSecretRef == nil) {
+		return fmt.Errorf("redis.url or redis.urlSecretRef is required for the redis provider")
+	}
+	if redis.URL != "" && redis.URLSecretRef != nil {
+		return fmt.Errorf("redis.url and redis.urlSecretRef are mutually exclusive")
+	}
+	if redis.PasswordSecretRef != nil && redis.URL == "" {
+		return fmt.Errorf("redis.passwordSecretRef requires redis.url")
+	}
+	return nil
+}
+
+// redisWiring emits REDIS_URL and REDIS_KEY_PREFIX for the Redis adapters,
+// sourcing credentials from Secrets and mounting the TLS CA bundle, which Node
+// picks up through NODE_EXTRA_CA_CERTS. An inline URL is switched to rediss://;
+// a URL from a Secret cannot be rewritten, so the adapters turn on TLS from
+// REDIS_TLS instead.
+func redisWiring(redis *appsv1alpha1.RedisCacheSpec, defaultPrefix string) podWiring {
+	var w podWiring
+	tls := redis.TLS != nil && redis.TLS.Enabled
+
+	if ref := redis.URLSecretRef; ref != nil {
+		w.addSecretEnv("REDIS_URL", ref.Name, secretKeyOrDefault(ref, "url"))
+	} else {
+		url := redis.URL
+		if tls {
+			url = strings.Replace(url, "redis://", "rediss://", 1)
+		}
+		if ref := redis.PasswordSecretRef; ref != nil {
+			// Declared before REDIS_URL so the kubelet expands $(REDIS_PASSWORD)
+			w.addSecretEnv("REDIS_PASSWORD", ref.Name, secretKeyOrDefault(ref, "password"))
+			url = withRedisPassword(url, "$(REDIS_PASSWORD)")
+		}
+		w.addEnv("REDIS_URL", url)
+	}
+
+	prefix := redis.KeyPrefix
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	w.addEnv("REDIS_KEY_PREFIX", prefix)
+
+	if tls {
+		w.addEnv("REDIS_TLS", "true")
+		if ref := redis.TLS.CASecretRef; ref != nil {
+			w.addSecretVolume("redis-tls-ca", ref.Name, redisCAMountPath)
+			w.addEnv("NODE_EXTRA_CA_CERTS", redisCAMountPath+"/"+secretKeyOrDefault(ref, "ca.crt"))
+		}
+	}
+	return w
+}
+
+// withRedisPassword replaces the password in the userinfo of a redis:// or
+// rediss:// URL, keeping any username.
+func withRedisPassword(url, password string) string {
+	scheme, rest, ok := strings.Cut(url, "://")
+	if !ok {
+		return url
+	}
+	user := ""
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		user, _, _ = strings.Cut(rest[:at], ":")
+		rest = rest[at+1:]
+	}
+	return scheme + "://" + user + ":" + password + "@" + rest
+}
+
+func secretKeyOrDefault(ref *appsv1alpha1.SecretKeyRef, key string) string {
+	if ref.Key != "" {
+		return ref.Key
+	}
+	return key
+}
@@ -19,36 +19,96 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+	"github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/internal/deadletter"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+)
+
+// Container resource defaults, kept in sync with the TypeScript manifest generator.
+var (
+	defaultCPURequest    = resource.MustParse("250m")
+	defaultMemoryRequest = resource.MustParse("512Mi")
+	defaultCPULimit      = resource.MustParse("1000m")
+	defaultMemoryLimit   = resource.MustParse("1Gi")
+)
+
+// Probe defaults, kept in sync with the TypeScript manifest generator.
+var (
+	defaultReadinessProbe = appsv1alpha1.ProbeSpec{
+		Path:                "/api/health",
+		Port:                appsv1alpha1.ContainerPort,
+		InitialDelaySeconds: ptr.To[int32](5),
+		PeriodSeconds:       ptr.To[int32](10),
+	}
+	defaultLivenessProbe = appsv1alpha1.ProbeSpec{
+		Path:                "/api/health",
+		Port:                appsv1alpha1.ContainerPort,
+		InitialDelaySeconds: ptr.To[int32](15),
+		PeriodSeconds:       ptr.To[int32](30),
+	}
+	defaultStartupProbe = appsv1alpha1.ProbeSpec{
+		Path:             "/api/health",
+		Port:             appsv1alpha1.ContainerPort,
+		PeriodSeconds:    ptr.To[int32](5),
+		FailureThreshold: ptr.To[int32](60),
+	}
 )
 
 // NextAppReconciler reconciles a NextApp object
 type NextAppReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+
+	// DeadLetters counts dead-lettered revalidation events. Optional.
+	DeadLetters *deadletter.Receiver
 }
 
 // +kubebuilder:rbac:groups=apps.kn-next.dev,resources=nextapps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kn-next.dev,resources=nextapps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kn-next.dev,resources=nextapps/finalizers,verbs=update
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=serving.knative.dev,resources=revisions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=serving.knative.dev,resources=domainmappings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sources.knative.dev,resources=kafkasources,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=eventing.knative.dev,resources=triggers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=eventing.knative.dev,resources=brokers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkatopics,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
 
 func (r *NextAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
@@ -60,224 +120,485 @@ func (r *NextAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 		return ctrl.Result{}, err
 	}
+	if !nextApp.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, &nextApp)
+	}
+	if controllerutil.AddFinalizer(&nextApp, teardownFinalizer) {
+		if err := r.Update(ctx, &nextApp); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	originalStatus := nextApp.Status.DeepCopy()
+
+	// Resolve the provider env contract up front so an invalid spec is
+	// rejected before any owned object is touched.
+	var wiring podWiring
+	wiring.merge(storageWiring(nextApp.Name, nextApp.Spec.Storage))
+	wiring.merge(domainsWiring(nextApp.Spec.Domains))
+	cacheWires, err := cacheWiring(&nextApp)
+	if err != nil {
+		logger.Error(err, "Invalid cache configuration")
+		r.invalidSpecEvent(&nextApp, err)
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+	wiring.merge(cacheWires)
+	var brokerURL string
+	if nextApp.Spec.Revalidation != nil && nextApp.Spec.Revalidation.Queue == appsv1alpha1.RevalidationQueueBroker {
+		if brokerURL, err = r.brokerURL(ctx, &nextApp); err != nil {
+			logger.Error(err, "Failed to resolve revalidation Broker")
+			return ctrl.Result{}, err
+		}
+	}
+	var topic string
+	if nextApp.Spec.Revalidation != nil && nextApp.Spec.Revalidation.Queue == appsv1alpha1.RevalidationQueueKafka {
+		if topic, err = r.revalidationTopic(ctx, &nextApp); err != nil {
+			logger.Error(err, "Failed to resolve revalidation topic")
+			return ctrl.Result{}, err
+		}
+	}
+	wiring.merge(revalidationWiring(&nextApp, brokerURL, topic))
+	if observabilityEnabled(&nextApp) {
+		wiring.addEnv("KN_APP_NAME", nextApp.Name)
+	}
+
+	// Hand edits found while applying the owned objects
+	drift := &driftReport{}
+	// Owned objects applied in this reconcile; the rest are pruned
+	applied := inventory{}
 
 	// 1. Create/Update ServiceAccount
-	sa := &corev1.ServiceAccount{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      nextApp.Name + "-sa",
-			Namespace: nextApp.Namespace,
-		},
+	sa := appServiceAccount(&nextApp)
+	if err := ctrl.SetControllerReference(&nextApp, sa, r.Scheme); err != nil {
+		return ctrl.Result{}, err
 	}
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, sa, func() error {
-		sa.AutomountServiceAccountToken = ptr.To(false)
-		return ctrl.SetControllerReference(&nextApp, sa, r.Scheme)
-	})
-	if err != nil {
+	if err := r.apply(ctx, &nextApp, drift, sa); err != nil {
 		logger.Error(err, "Failed to reconcile ServiceAccount")
 		return ctrl.Result{}, err
 	}
 
 	// 2. Create/Update PVC if Bytecode Caching is enabled
+	var pvc *corev1.PersistentVolumeClaim
 	if nextApp.Spec.Cache != nil && nextApp.Spec.Cache.EnableBytecodeCache {
 		size := nextApp.Spec.Cache.BytecodeCacheSize
 		if size == "" {
-			size = "512Mi"
-		}
-		pvc := &corev1.PersistentVolumeClaim{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      nextApp.Name + "-bytecode-cache",
-				Namespace: nextApp.Namespace,
-			},
+			size = appsv1alpha1.DefaultBytecodeCacheSize
 		}
-		_, err = controllerutil.CreateOrUpdate(ctx, r.Client, pvc, func() error {
-			if pvc.Spec.AccessModes == nil {
-				pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
-			}
-			if pvc.Spec.Resources.Requests == nil {
-				pvc.Spec.Resources.Requests = corev1.ResourceList{}
-			}
-			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(size)
-			return ctrl.SetControllerReference(&nextApp, pvc, r.Scheme)
-		})
+		quantity, err := resource.ParseQuantity(size)
 		if err != nil {
+			err = fmt.Errorf("invalid bytecodeCacheSize %q: %w", size, err)
+			logger.Error(err, "Invalid cache configuration")
+			r.invalidSpecEvent(&nextApp, err)
+			return ctrl.Result{}, reconcile.TerminalError(err)
+		}
+		pvc = bytecodeCachePVC(&nextApp, quantity)
+		live := &corev1.PersistentVolumeClaim{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(pvc), live); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Failed to get PVC")
+			return ctrl.Result{}, err
+		}
+		keepImmutablePVCFields(pvc, live)
+		if err := ctrl.SetControllerReference(&nextApp, pvc, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.apply(ctx, &nextApp, drift, pvc); err != nil {
 			logger.Error(err, "Failed to reconcile PVC")
 			return ctrl.Result{}, err
 		}
+		applied.add("PersistentVolumeClaim", pvc.Name)
 	}
 
-	// 3. Create/Update Knative Service
+	// 3. Roll back a failed revision and create/update the Knative Service
+	rollbackRequeue, err := r.reconcileRollback(ctx, &nextApp)
+	if err != nil {
+		logger.Error(err, "Failed to check latest revision")
+		return ctrl.Result{}, err
+	}
+
+	// The desired Service is built from scratch so fields the operator no
+	// longer sets are released; the live one is only read for its status.
+	live := &servingv1.Service{}
+	if err := r.Get(ctx, req.NamespacedName, live); client.IgnoreNotFound(err) != nil {
+		logger.Error(err, "Failed to get Knative Service")
+		return ctrl.Result{}, err
+	}
 	ksvc := &servingv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      nextApp.Name,
 			Namespace: nextApp.Namespace,
+			Labels: map[string]string{
+				"app":          nextApp.Name,
+				"generated-by": "kn-next-operator",
+				nextAppLabel:   nextApp.Name,
+			},
 		},
 	}
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, ksvc, func() error {
-		if ksvc.Labels == nil {
-			ksvc.Labels = make(map[string]string)
-		}
-		ksvc.Labels["app"] = nextApp.Name
-		ksvc.Labels["generated-by"] = "kn-next-operator"
+	var rolloutRequeue time.Duration
 
-		annotations := map[string]string{
-			"autoscaling.knative.dev/min-scale": "0",
-			"autoscaling.knative.dev/max-scale": "10",
-		}
-		if nextApp.Spec.Scaling != nil {
-			annotations["autoscaling.knative.dev/min-scale"] = fmt.Sprintf("%d", nextApp.Spec.Scaling.MinScale)
-			annotations["autoscaling.knative.dev/max-scale"] = fmt.Sprintf("%d", nextApp.Spec.Scaling.MaxScale)
+	minScale, maxScale := appsv1alpha1.DefaultMinScale, appsv1alpha1.DefaultMaxScale
+	if nextApp.Spec.Scaling != nil {
+		minScale = nextApp.Spec.Scaling.MinScale
+		maxScale = max(maxScale, minScale)
+		if nextApp.Spec.Scaling.MaxScale > 0 {
+			maxScale = nextApp.Spec.Scaling.MaxScale
 		}
+	}
+	annotations := map[string]string{
+		"autoscaling.knative.dev/min-scale": fmt.Sprintf("%d", minScale),
+		"autoscaling.knative.dev/max-scale": fmt.Sprintf("%d", maxScale),
+	}
 
-		if nextApp.Spec.Preview != nil && nextApp.Spec.Preview.Enabled {
-			ksvc.Labels["environment"] = "preview"
-			ksvc.Labels["pr-id"] = nextApp.Spec.Preview.PRID
-			
-			// Override max-scale to 1 to save cluster resources on previews
-			annotations["autoscaling.knative.dev/max-scale"] = "1"
-			annotations["autoscaling.knative.dev/min-scale"] = "0"
-			// Set a very short scale-to-zero window
-			annotations["autoscaling.knative.dev/scale-to-zero-pod-retention-period"] = "30s"
-		}
+	if nextApp.Spec.Preview != nil && nextApp.Spec.Preview.Enabled {
+		ksvc.Labels["environment"] = "preview"
+		ksvc.Labels["pr-id"] = nextApp.Spec.Preview.PRID
 
-		var envVars []corev1.EnvVar
-		envVars = append(envVars, corev1.EnvVar{Name: "HOSTNAME", Value: "0.0.0.0"})
-		envVars = append(envVars, corev1.EnvVar{Name: "NODE_ENV", Value: "production"})
+		// Override max-scale to 1 to save cluster resources on previews
+		annotations["autoscaling.knative.dev/max-scale"] = "1"
+		annotations["autoscaling.knative.dev/min-scale"] = "0"
+		// Set a very short scale-to-zero window
+		annotations["autoscaling.knative.dev/scale-to-zero-pod-retention-period"] = "30s"
+	}
 
-		if nextApp.Spec.Storage != nil && nextApp.Spec.Storage.Provider != "" {
-			envVars = append(envVars, corev1.EnvVar{Name: "STORAGE_PROVIDER", Value: nextApp.Spec.Storage.Provider})
-			envVars = append(envVars, corev1.EnvVar{Name: "GCS_BUCKET_NAME", Value: nextApp.Spec.Storage.Bucket})
-		}
-		if nextApp.Spec.Cache != nil && nextApp.Spec.Cache.Provider != "" {
-			envVars = append(envVars, corev1.EnvVar{Name: "CACHE_PROVIDER", Value: nextApp.Spec.Cache.Provider})
-			envVars = append(envVars, corev1.EnvVar{Name: "REDIS_URL", Value: nextApp.Spec.Cache.URL})
-			if nextApp.Spec.Cache.EnableBytecodeCache {
-				envVars = append(envVars, corev1.EnvVar{Name: "NODE_COMPILE_CACHE", Value: "/cache/bytecode/latest"})
-			}
-		}
-		if nextApp.Spec.Revalidation != nil && nextApp.Spec.Revalidation.Queue != "" {
-			envVars = append(envVars, corev1.EnvVar{Name: "KAFKA_BROKER_URL", Value: nextApp.Spec.Revalidation.KafkaBrokerUrl})
-			envVars = append(envVars, corev1.EnvVar{Name: "KAFKA_REVALIDATION_TOPIC", Value: fmt.Sprintf("%s-revalidation", nextApp.Name)})
+	if observabilityEnabled(&nextApp) {
+		for k, v := range prometheusAnnotations(nextApp.Spec.Observability) {
+			annotations[k] = v
 		}
+	}
 
-		var envFrom []corev1.EnvFromSource
-		if nextApp.Spec.Secrets != nil {
-			for _, secretName := range nextApp.Spec.Secrets.EnvFrom {
-				envFrom = append(envFrom, corev1.EnvFromSource{
-					SecretRef: &corev1.SecretEnvSource{
-						LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
-					},
-				})
-			}
-		}
+	var envVars []corev1.EnvVar
+	envVars = append(envVars, corev1.EnvVar{Name: "HOSTNAME", Value: "0.0.0.0"})
+	envVars = append(envVars, corev1.EnvVar{Name: "NODE_ENV", Value: "production"})
+
+	envVars = append(envVars, wiring.env...)
 
-		var volumes []corev1.Volume
-		var volumeMounts []corev1.VolumeMount
-		if nextApp.Spec.Cache != nil && nextApp.Spec.Cache.EnableBytecodeCache {
-			volumes = append(volumes, corev1.Volume{
-				Name: "bytecode-cache",
-				VolumeSource: corev1.VolumeSource{
-					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: nextApp.Name + "-bytecode-cache",
-					},
+	var envFrom []corev1.EnvFromSource
+	if nextApp.Spec.Secrets != nil {
+		envVars = append(envVars, secretKeyEnvVars(nextApp.Spec.Secrets.EnvMap)...)
+		for _, secretName := range nextApp.Spec.Secrets.EnvFrom {
+			envFrom = append(envFrom, corev1.EnvFromSource{
+				SecretRef: &corev1.SecretEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
 				},
 			})
-			volumeMounts = append(volumeMounts, corev1.VolumeMount{
-				Name:      "bytecode-cache",
-				MountPath: "/cache/bytecode",
-			})
-		}
-
-		cc := int64(100)
-		if nextApp.Spec.Scaling != nil && nextApp.Spec.Scaling.ContainerConcurrency > 0 {
-			cc = int64(nextApp.Spec.Scaling.ContainerConcurrency)
 		}
+	}
 
-		ksvc.Spec.Template.ObjectMeta.Annotations = annotations
-		ksvc.Spec.Template.Spec.ServiceAccountName = nextApp.Name + "-sa"
-		ksvc.Spec.Template.Spec.ContainerConcurrency = &cc
-		ksvc.Spec.Template.Spec.Containers = []corev1.Container{
-			{
-				Image:        nextApp.Spec.Image,
-				Env:          envVars,
-				EnvFrom:      envFrom,
-				VolumeMounts: volumeMounts,
-				Ports: []corev1.ContainerPort{
-					{ContainerPort: 3000},
-				},
-				ReadinessProbe: &corev1.Probe{
-					ProbeHandler: corev1.ProbeHandler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path: "/api/health",
-							Port: intstr.FromInt(3000),
-						},
-					},
-					InitialDelaySeconds: 5,
-					PeriodSeconds:       10,
+	volumes := wiring.volumes
+	volumeMounts := wiring.mounts
+	if nextApp.Spec.Cache != nil && nextApp.Spec.Cache.EnableBytecodeCache {
+		volumes = append(volumes, corev1.Volume{
+			Name: "bytecode-cache",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: nextApp.Name + "-bytecode-cache",
 				},
 			},
-		}
-		ksvc.Spec.Template.Spec.Volumes = volumes
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "bytecode-cache",
+			MountPath: "/cache/bytecode",
+		})
+	}
 
-		return ctrl.SetControllerReference(&nextApp, ksvc, r.Scheme)
-	})
-	if err != nil {
+	var probes appsv1alpha1.ProbesSpec
+	if nextApp.Spec.Probes != nil {
+		probes = *nextApp.Spec.Probes
+	}
+	var startupProbe *corev1.Probe
+	if probes.Startup != nil {
+		startupProbe = buildProbe(probes.Startup, defaultStartupProbe)
+	}
+
+	cc := int64(appsv1alpha1.DefaultContainerConcurrency)
+	if nextApp.Spec.Scaling != nil && nextApp.Spec.Scaling.ContainerConcurrency > 0 {
+		cc = int64(nextApp.Spec.Scaling.ContainerConcurrency)
+	}
+
+	ksvc.Spec.Template.ObjectMeta.Annotations = annotations
+	ksvc.Spec.Template.Spec.ServiceAccountName = nextApp.Name + "-sa"
+	ksvc.Spec.Template.Spec.ContainerConcurrency = &cc
+	ksvc.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Image:        nextApp.Spec.Image,
+			Env:          envVars,
+			EnvFrom:      envFrom,
+			VolumeMounts: volumeMounts,
+			Resources:    containerResources(nextApp.Spec.Scaling),
+			Ports: []corev1.ContainerPort{
+				{ContainerPort: appsv1alpha1.ContainerPort},
+			},
+			ReadinessProbe: buildProbe(probes.Readiness, defaultReadinessProbe),
+			LivenessProbe:  buildProbe(probes.Liveness, defaultLivenessProbe),
+			StartupProbe:   startupProbe,
+		},
+	}
+	ksvc.Spec.Template.Spec.Volumes = volumes
+
+	if traffic := rollbackTraffic(&nextApp); traffic != nil {
+		ksvc.Spec.Traffic = traffic
+	} else if nextApp.Spec.Rollout != nil {
+		ksvc.Spec.Traffic, rolloutRequeue = progressRollout(&nextApp, live, time.Now())
+	} else {
+		// No rollout strategy or rollback: traffic is left out, which hands
+		// routing back to Knative
+		nextApp.Status.Rollout = nil
+	}
+
+	if err := ctrl.SetControllerReference(&nextApp, ksvc, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.apply(ctx, &nextApp, drift, ksvc); err != nil {
 		logger.Error(err, "Failed to reconcile Knative Service")
 		return ctrl.Result{}, err
 	}
+	applied.add("Service", ksvc.Name)
 
-	// 4. Create/Update KafkaSource if Revalidation is enabled using Unstructured to avoid Eventing proto deps
-	if nextApp.Spec.Revalidation != nil && nextApp.Spec.Revalidation.Queue == "kafka" {
-		topic := fmt.Sprintf("%s-revalidation", nextApp.Name)
-		kafkaSource := &unstructured.Unstructured{}
-		kafkaSource.SetAPIVersion("sources.knative.dev/v1beta1")
-		kafkaSource.SetKind("KafkaSource")
-		kafkaSource.SetName(nextApp.Name + "-revalidation-source")
-		kafkaSource.SetNamespace(nextApp.Namespace)
-
-		_, err = controllerutil.CreateOrUpdate(ctx, r.Client, kafkaSource, func() error {
-			spec := map[string]interface{}{
-				"consumerGroup": nextApp.Name + "-revalidation",
-				"bootstrapServers": []interface{}{
-					nextApp.Spec.Revalidation.KafkaBrokerUrl,
-				},
-				"topics": []interface{}{
-					topic,
-				},
-				"sink": map[string]interface{}{
-					"ref": map[string]interface{}{
-						"apiVersion": "serving.knative.dev/v1",
-						"kind":       "Service",
-						"name":       nextApp.Name + "-revalidator",
-					},
-				},
-			}
-			kafkaSource.Object["spec"] = spec
-			return ctrl.SetControllerReference(&nextApp, kafkaSource, r.Scheme)
-		})
-		if err != nil {
-			logger.Error(err, "Failed to reconcile KafkaSource")
+	// 4. Create/Update DomainMappings for custom domains
+	if err := r.reconcileDomains(ctx, &nextApp); err != nil {
+		logger.Error(err, "Failed to reconcile custom domains")
+		return ctrl.Result{}, err
+	}
+
+	// 5. Create/Update KafkaSource or Trigger and the revalidator sink if Revalidation is enabled
+	if err := r.reconcileRevalidation(ctx, &nextApp, ksvc, brokerURL, topic, drift, applied); err != nil {
+		logger.Error(err, "Failed to reconcile revalidation")
+		return ctrl.Result{}, err
+	}
+
+	// 6. Create/Update ServiceMonitor and Grafana dashboard if Observability is enabled
+	if observabilityEnabled(&nextApp) {
+		if err := r.reconcileObservability(ctx, &nextApp, applied); err != nil {
+			logger.Error(err, "Failed to reconcile observability resources")
 			return ctrl.Result{}, err
 		}
 	}
 
-	// 5. Update Status
-	if ksvc.Status.URL != nil {
-		nextApp.Status.URL = ksvc.Status.URL.String()
+	// 7. Prune owned objects for features the spec turned off
+	if err := r.reconcilePrune(ctx, &nextApp, applied); err != nil {
+		logger.Error(err, "Failed to prune owned objects")
+		return ctrl.Result{}, err
+	}
+
+	// 8. Update Status
+	updateServiceStatus(&nextApp, ksvc)
+	updateCacheStatus(&nextApp, pvc)
+	updateReadyStatus(&nextApp)
+	updateDriftStatus(&nextApp, drift)
+	nextApp.Status.ObservedGeneration = nextApp.Generation
+	if !equality.Semantic.DeepEqual(originalStatus, &nextApp.Status) {
 		if err := r.Status().Update(ctx, &nextApp); err != nil {
 			return ctrl.Result{}, err
 		}
+		if ready := readyTransition(originalStatus, &nextApp.Status); ready != nil && r.Recorder != nil {
+			eventType := corev1.EventTypeNormal
+			if ready.Status == metav1.ConditionFalse {
+				eventType = corev1.EventTypeWarning
+			}
+			r.Recorder.Eventf(&nextApp, nil, eventType, ready.Reason, "Status", "Ready is %s: %s", ready.Status, ready.Message)
+		}
 	}
 
 	logger.Info("Successfully reconciled NextApp", "name", nextApp.Name, "url", nextApp.Status.URL)
+	if nextApp.Spec.Revalidation != nil && nextApp.Spec.Revalidation.Queue == appsv1alpha1.RevalidationQueueBroker && brokerURL == "" {
+		// The Broker is not watched, poll until it is addressable
+		logger.Info("Waiting for revalidation Broker", "broker", brokerName(&nextApp))
+		return ctrl.Result{RequeueAfter: brokerRetryInterval}, nil
+	}
+	if rollbackRequeue > 0 && (rolloutRequeue == 0 || rollbackRequeue < rolloutRequeue) {
+		logger.Info("Waiting for latest revision to become ready", "deadlineIn", rollbackRequeue)
+		return ctrl.Result{RequeueAfter: rollbackRequeue}, nil
+	}
+	if rolloutRequeue > 0 {
+		logger.Info("Rollout step in progress", "canary", nextApp.Status.Rollout.CanaryRevision,
+			"percent", nextApp.Status.Rollout.CanaryPercent, "nextStepIn", rolloutRequeue)
+		return ctrl.Result{RequeueAfter: rolloutRequeue}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// invalidSpecEvent reports a spec the reconciler gives up on until it changes.
+func (r *NextAppReconciler) invalidSpecEvent(nextApp *appsv1alpha1.NextApp, err error) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(nextApp, nil, corev1.EventTypeWarning, "InvalidSpec", "Reconcile", "%v", err)
+	}
+}
+
+// appServiceAccount builds the ServiceAccount the app and its purge Job run
+// under, so workload identity bound to it covers both.
+func appServiceAccount(nextApp *appsv1alpha1.NextApp) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextApp.Name + "-sa",
+			Namespace: nextApp.Namespace,
+		},
+		AutomountServiceAccountToken: ptr.To(false),
+	}
+}
+
+// bytecodeCachePVC builds the desired bytecode cache claim.
+func bytecodeCachePVC(nextApp *appsv1alpha1.NextApp, size resource.Quantity) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextApp.Name + "-bytecode-cache",
+			Namespace: nextApp.Namespace,
+			Labels: map[string]string{
+				"app":          nextApp.Name,
+				"generated-by": "kn-next-operator",
+				nextAppLabel:   nextApp.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+}
+
+// keepImmutablePVCFields copies the fields the API server defaults and then
+// freezes from the live claim. Claims written with Update before the switch to
+// server-side apply hand their ownership to the operator, and applying them
+// unset would be rejected as a change to an immutable field.
+func keepImmutablePVCFields(pvc, live *corev1.PersistentVolumeClaim) {
+	if live.UID == "" {
+		return
+	}
+	pvc.Spec.AccessModes = live.Spec.AccessModes
+	pvc.Spec.StorageClassName = live.Spec.StorageClassName
+	pvc.Spec.VolumeMode = live.Spec.VolumeMode
+	pvc.Spec.VolumeName = live.Spec.VolumeName
+}
+
+// containerResources builds the CPU/memory requests and limits for the Next.js
+// container, falling back to the defaults for anything the spec leaves unset.
+func containerResources(scaling *appsv1alpha1.ScalingSpec) corev1.ResourceRequirements {
+	cpuRequest, memoryRequest := defaultCPURequest, defaultMemoryRequest
+	cpuLimit, memoryLimit := defaultCPULimit, defaultMemoryLimit
+	if scaling != nil {
+		if scaling.CPURequest != nil {
+			cpuRequest = *scaling.CPURequest
+		}
+		if scaling.MemoryRequest != nil {
+			memoryRequest = *scaling.MemoryRequest
+		}
+		if scaling.CPULimit != nil {
+			cpuLimit = *scaling.CPULimit
+		}
+		if scaling.MemoryLimit != nil {
+			memoryLimit = *scaling.MemoryLimit
+		}
+	}
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    cpuRequest,
+			corev1.ResourceMemory: memoryRequest,
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    cpuLimit,
+			corev1.ResourceMemory: memoryLimit,
+		},
+	}
+}
+
+// secretKeyEnvVars renders an envMap as secretKeyRef env vars, sorted by name so
+// the pod template stays stable across reconciles.
+func secretKeyEnvVars(envMap map[string]appsv1alpha1.SecretKeyRef) []corev1.EnvVar {
+	names := make([]string, 0, len(envMap))
+	for name := range envMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	envVars := make([]corev1.EnvVar, 0, len(names))
+	for _, name := range names {
+		ref := envMap[name]
+		key := ref.Key
+		if key == "" {
+			key = name
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+					Key:                  key,
+				},
+			},
+		})
+	}
+	return envVars
+}
+
+// buildProbe turns a ProbeSpec into an HTTP GET probe, filling any unset
+// field from the given defaults.
+func buildProbe(spec *appsv1alpha1.ProbeSpec, defaults appsv1alpha1.ProbeSpec) *corev1.Probe {
+	p := defaults
+	if spec != nil {
+		if spec.Path != "" {
+			p.Path = spec.Path
+		}
+		if spec.Port != 0 {
+			p.Port = spec.Port
+		}
+		if spec.InitialDelaySeconds != nil {
+			p.InitialDelaySeconds = spec.InitialDelaySeconds
+		}
+		if spec.PeriodSeconds != nil {
+			p.PeriodSeconds = spec.PeriodSeconds
+		}
+		if spec.TimeoutSeconds != nil {
+			p.TimeoutSeconds = spec.TimeoutSeconds
+		}
+		if spec.SuccessThreshold != nil {
+			p.SuccessThreshold = spec.SuccessThreshold
+		}
+		if spec.FailureThreshold != nil {
+			p.FailureThreshold = spec.FailureThreshold
+		}
+	}
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: p.Path,
+				Port: intstr.FromInt32(p.Port),
+			},
+		},
+		InitialDelaySeconds: ptr.Deref(p.InitialDelaySeconds, 0),
+		PeriodSeconds:       ptr.Deref(p.PeriodSeconds, 0),
+		TimeoutSeconds:      ptr.Deref(p.TimeoutSeconds, 0),
+		SuccessThreshold:    ptr.Deref(p.SuccessThreshold, 0),
+		FailureThreshold:    ptr.Deref(p.FailureThreshold, 0),
+	}
+}
+
 func (r *NextAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	blder := ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1alpha1.NextApp{}).
 		Owns(&servingv1.Service{}).
+		Owns(&servingv1beta1.DomainMapping{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Service{}).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(purgeJobToNextApp),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return NextAppSelector().Matches(labels.Set(obj.GetLabels()))
+			})))
+	// Eventing, Strimzi and Prometheus objects are watched only when their CRDs are installed
+	for _, gvk := range []schema.GroupVersionKind{kafkaSourceGVK, triggerGVK, kafkaTopicGVK, serviceMonitorGVK, prometheusRuleGVK} {
+		_, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			mgr.GetLogger().Info("CRD not installed, not watching", "kind", gvk.Kind)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		owned := &unstructured.Unstructured{}
+		owned.SetGroupVersionKind(gvk)
+		blder = blder.Owns(owned)
+	}
+	return blder.
 		Named("nextapp").
 		Complete(r)
 }
@@ -21,8 +21,12 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,8 +73,9 @@ var _ = Describe("NextApp Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &NextAppReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: events.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -82,3 +87,106 @@ var _ = Describe("NextApp Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("containerResources", func() {
+	It("should fall back to the generator defaults when scaling is unset", func() {
+		res := containerResources(nil)
+		Expect(res.Requests.Cpu().String()).To(Equal("250m"))
+		Expect(res.Requests.Memory().String()).To(Equal("512Mi"))
+		Expect(res.Limits.Cpu().String()).To(Equal("1"))
+		Expect(res.Limits.Memory().String()).To(Equal("1Gi"))
+	})
+
+	It("should use the quantities from the scaling spec", func() {
+		res := containerResources(&appsv1alpha1.ScalingSpec{
+			CPURequest:  ptrQuantity("500m"),
+			MemoryLimit: ptrQuantity("2Gi"),
+		})
+		Expect(res.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("500m")))
+		Expect(res.Requests.Memory().String()).To(Equal("512Mi"))
+		Expect(res.Limits.Memory().String()).To(Equal("2Gi"))
+	})
+})
+
+func ptrQuantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+var _ = Describe("buildProbe", func() {
+	It("should use the defaults when no probe is configured", func() {
+		probe := buildProbe(nil, defaultReadinessProbe)
+		Expect(probe.HTTPGet.Path).To(Equal("/api/health"))
+		Expect(probe.HTTPGet.Port.IntValue()).To(Equal(3000))
+		Expect(probe.InitialDelaySeconds).To(Equal(int32(5)))
+		Expect(probe.PeriodSeconds).To(Equal(int32(10)))
+	})
+
+	It("should override only the fields that are set", func() {
+		probe := buildProbe(&appsv1alpha1.ProbeSpec{
+			Path:             "/healthz",
+			FailureThreshold: ptr.To[int32](30),
+		}, defaultLivenessProbe)
+		Expect(probe.HTTPGet.Path).To(Equal("/healthz"))
+		Expect(probe.HTTPGet.Port.IntValue()).To(Equal(3000))
+		Expect(probe.InitialDelaySeconds).To(Equal(int32(15)))
+		Expect(probe.FailureThreshold).To(Equal(int32(30)))
+	})
+
+	It("should keep an explicit zero initial delay", func() {
+		probe := buildProbe(&appsv1alpha1.ProbeSpec{InitialDelaySeconds: ptr.To[int32](0)}, defaultLivenessProbe)
+		Expect(probe.InitialDelaySeconds).To(BeZero())
+		Expect(probe.PeriodSeconds).To(Equal(int32(30)))
+	})
+})
+
+var _ = Describe("secretKeyEnvVars", func() {
+	It("should render sorted secretKeyRef env vars defaulting the key to the env name", func() {
+		envVars := secretKeyEnvVars(map[string]appsv1alpha1.SecretKeyRef{
+			"STRIPE_KEY":   {Name: "platform-secrets", Key: "stripe-api-key"},
+			"DATABASE_URL": {Name: "db-credentials"},
+		})
+		Expect(envVars).To(HaveLen(2))
+		Expect(envVars[0].Name).To(Equal("DATABASE_URL"))
+		Expect(envVars[0].ValueFrom.SecretKeyRef.Name).To(Equal("db-credentials"))
+		Expect(envVars[0].ValueFrom.SecretKeyRef.Key).To(Equal("DATABASE_URL"))
+		Expect(envVars[1].Name).To(Equal("STRIPE_KEY"))
+		Expect(envVars[1].ValueFrom.SecretKeyRef.Key).To(Equal("stripe-api-key"))
+	})
+})
+
+var _ = Describe("bytecodeCachePVC", func() {
+	nextApp := &appsv1alpha1.NextApp{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "store"}}
+	size := resource.MustParse("2Gi")
+
+	It("should request the cache size as a ReadWriteOnce claim", func() {
+		pvc := bytecodeCachePVC(nextApp, size)
+		Expect(pvc.Name).To(Equal("shop-bytecode-cache"))
+		Expect(pvc.Labels).To(HaveKeyWithValue(nextAppLabel, "shop"))
+		Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
+		Expect(pvc.Spec.StorageClassName).To(BeNil())
+	})
+
+	It("should keep the immutable fields of an existing claim", func() {
+		pvc := bytecodeCachePVC(nextApp, size)
+		live := bytecodeCachePVC(nextApp, resource.MustParse("1Gi"))
+		live.UID = "uid"
+		live.Spec.StorageClassName = ptr.To("standard")
+		live.Spec.VolumeName = "pvc-1234"
+		live.Spec.VolumeMode = ptr.To(corev1.PersistentVolumeFilesystem)
+
+		keepImmutablePVCFields(pvc, live)
+		Expect(pvc.Spec.StorageClassName).To(HaveValue(Equal("standard")))
+		Expect(pvc.Spec.VolumeName).To(Equal("pvc-1234"))
+		Expect(pvc.Spec.VolumeMode).To(HaveValue(Equal(corev1.PersistentVolumeFilesystem)))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
+	})
+
+	It("should leave a new claim to the cluster defaults", func() {
+		pvc := bytecodeCachePVC(nextApp, size)
+		keepImmutablePVCFields(pvc, &corev1.PersistentVolumeClaim{})
+		Expect(pvc.Spec.StorageClassName).To(BeNil())
+		Expect(pvc.Spec.VolumeName).To(BeEmpty())
+	})
+})
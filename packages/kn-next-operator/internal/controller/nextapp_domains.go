@@ -0,0 +1,262 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// nextAppLabel marks objects created for a NextApp so stale ones can be found
+// and pruned.
+const nextAppLabel = "apps.kn-next.dev/nextapp"
+
+// NextAppSelector matches the objects created for a NextApp by nextAppLabel.
+// The manager caches only the Jobs and core Services it matches, so watching
+// purge Jobs and metrics Services does not cache every one in the cluster.
+func NextAppSelector() labels.Selector {
+	requirement, err := labels.NewRequirement(nextAppLabel, selection.Exists, nil)
+	if err != nil {
+		panic(err)
+	}
+	return labels.NewSelector().Add(*requirement)
+}
+
+// nextAppNamespaceLabel records the NextApp's namespace on objects created
+// outside it, which owner references cannot point back from.
+const nextAppNamespaceLabel = "apps.kn-next.dev/namespace"
+
+// reconcileDomains maps every spec.domains host to the app's Knative Service
+// with a DomainMapping named after the host, requests cert-manager
+// Certificates where an issuer is set, prunes mappings for removed hosts and
+// Certificates for removed hosts or issuers, and records each domain's readiness in nextApp.Status.Domains.
+//
+// Knative only accepts a DomainMapping once the host is claimed for the
+// namespace, either by a ClusterDomainClaim or by enabling
+// autocreate-cluster-domain-claims in config-network.
+func (r *NextAppReconciler) reconcileDomains(ctx context.Context, nextApp *appsv1alpha1.NextApp) error {
+	var statuses []appsv1alpha1.DomainStatus
+	wanted := make(map[string]bool, len(nextApp.Spec.Domains))
+	for _, domain := range nextApp.Spec.Domains {
+		wanted[domain.Host] = true
+		secretName := domainTLSSecret(domain)
+		if domain.TLS != nil && domain.TLS.IssuerRef != nil {
+			if err := r.reconcileCertificate(ctx, nextApp, domain, secretName); err != nil {
+				return err
+			}
+		} else if err := r.deleteCertificate(ctx, nextApp, domain.Host); err != nil {
+			// The host stays but its issuer was removed
+			return err
+		}
+
+		dm := &servingv1beta1.DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      domain.Host,
+				Namespace: nextApp.Namespace,
+			},
+		}
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, dm, func() error {
+			if dm.Labels == nil {
+				dm.Labels = make(map[string]string)
+			}
+			dm.Labels["app"] = nextApp.Name
+			dm.Labels["generated-by"] = "kn-next-operator"
+			dm.Labels[nextAppLabel] = nextApp.Name
+			if domain.Locale != "" {
+				dm.Labels["apps.kn-next.dev/locale"] = domain.Locale
+			} else {
+				delete(dm.Labels, "apps.kn-next.dev/locale")
+			}
+
+			dm.Spec.Ref = duckv1.KReference{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Name:       nextApp.Name,
+				Namespace:  nextApp.Namespace,
+			}
+			dm.Spec.TLS = nil
+			if secretName != "" {
+				dm.Spec.TLS = &servingv1beta1.SecretTLS{SecretName: secretName}
+			}
+			return ctrl.SetControllerReference(nextApp, dm, r.Scheme)
+		})
+		if err != nil {
+			return fmt.Errorf("reconcile DomainMapping %s: %w", domain.Host, err)
+		}
+		statuses = append(statuses, domainStatus(domain, dm))
+	}
+
+	var mappings servingv1beta1.DomainMappingList
+	if err := r.List(ctx, &mappings, client.InNamespace(nextApp.Namespace), client.MatchingLabels{nextAppLabel: nextApp.Name}); err != nil {
+		return fmt.Errorf("list DomainMappings: %w", err)
+	}
+	for i := range mappings.Items {
+		dm := &mappings.Items[i]
+		if wanted[dm.Name] || !metav1.IsControlledBy(dm, nextApp) {
+			continue
+		}
+		if err := r.deleteDomain(ctx, nextApp, dm); err != nil {
+			return err
+		}
+	}
+
+	nextApp.Status.Domains = statuses
+	return nil
+}
+
+// reconcileCertificate requests a cert-manager Certificate for a domain,
+// skipping it when cert-manager is not installed.
+func (r *NextAppReconciler) reconcileCertificate(ctx context.Context, nextApp *appsv1alpha1.NextApp, domain appsv1alpha1.DomainSpec, secretName string) error {
+	logger := logf.FromContext(ctx)
+
+	kind := domain.TLS.IssuerRef.Kind
+	if kind == "" {
+		kind = "ClusterIssuer"
+	}
+	cert := newCertificate(domain.Host, nextApp.Namespace)
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, cert, func() error {
+		cert.SetLabels(map[string]string{
+			"app":          nextApp.Name,
+			"generated-by": "kn-next-operator",
+			nextAppLabel:   nextApp.Name,
+		})
+		cert.Object["spec"] = map[string]interface{}{
+			"secretName": secretName,
+			"dnsNames":   []interface{}{domain.Host},
+			"issuerRef": map[string]interface{}{
+				"name":  domain.TLS.IssuerRef.Name,
+				"kind":  kind,
+				"group": "cert-manager.io",
+			},
+		}
+		return ctrl.SetControllerReference(nextApp, cert, r.Scheme)
+	})
+	if meta.IsNoMatchError(err) {
+		logger.Info("cert-manager Certificate CRD not installed, skipping", "name", cert.GetName())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reconcile Certificate %s: %w", domain.Host, err)
+	}
+	return nil
+}
+
+// deleteDomain removes a DomainMapping for a host that is no longer in the
+// spec, along with the Certificate requested for it.
+func (r *NextAppReconciler) deleteDomain(ctx context.Context, nextApp *appsv1alpha1.NextApp, dm *servingv1beta1.DomainMapping) error {
+	if err := r.Delete(ctx, dm); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete DomainMapping %s: %w", dm.Name, err)
+	}
+	return r.deleteCertificate(ctx, nextApp, dm.Name)
+}
+
+// deleteCertificate removes the Certificate the NextApp requested for host,
+// if any, leaving Certificates created by others in place.
+func (r *NextAppReconciler) deleteCertificate(ctx context.Context, nextApp *appsv1alpha1.NextApp, host string) error {
+	cert := newCertificate(host, nextApp.Namespace)
+	err := r.Get(ctx, types.NamespacedName{Name: host, Namespace: nextApp.Namespace}, cert)
+	if meta.IsNoMatchError(err) || errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get Certificate %s: %w", host, err)
+	}
+	if !metav1.IsControlledBy(cert, nextApp) {
+		return nil
+	}
+	if err := r.Delete(ctx, cert); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete Certificate %s: %w", host, err)
+	}
+	return nil
+}
+
+func newCertificate(name, namespace string) *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetAPIVersion("cert-manager.io/v1")
+	cert.SetKind("Certificate")
+	cert.SetName(name)
+	cert.SetNamespace(namespace)
+	return cert
+}
+
+// domainsWiring exposes the spec.domains hosts to the node server as the
+// comma-separated KN_DOMAINS. It only trusts K-Original-Host when the header
+// names one of them.
+func domainsWiring(domains []appsv1alpha1.DomainSpec) podWiring {
+	var w podWiring
+	if len(domains) == 0 {
+		return w
+	}
+	hosts := make([]string, len(domains))
+	for i, domain := range domains {
+		hosts[i] = domain.Host
+	}
+	w.addEnv("KN_DOMAINS", strings.Join(hosts, ","))
+	return w
+}
+
+// domainTLSSecret returns the Secret a domain terminates TLS with, or an empty
+// string for plain HTTP.
+func domainTLSSecret(domain appsv1alpha1.DomainSpec) string {
+	if domain.TLS == nil {
+		return ""
+	}
+	if domain.TLS.SecretName != "" {
+		return domain.TLS.SecretName
+	}
+	return domain.Host + "-tls"
+}
+
+func domainStatus(domain appsv1alpha1.DomainSpec, dm *servingv1beta1.DomainMapping) appsv1alpha1.DomainStatus {
+	status := appsv1alpha1.DomainStatus{
+		Host:   domain.Host,
+		Locale: domain.Locale,
+		Ready:  metav1.ConditionUnknown,
+	}
+	if dm.Status.URL != nil {
+		status.URL = dm.Status.URL.String()
+	}
+	if dm.Status.ObservedGeneration != dm.Generation {
+		status.Message = "Waiting for Knative to observe the DomainMapping"
+		return status
+	}
+	if ready := dm.Status.GetCondition(apis.ConditionReady); ready != nil {
+		status.Ready = metav1.ConditionStatus(ready.Status)
+		if !ready.IsTrue() {
+			status.Message = ready.Message
+		}
+	}
+	return status
+}
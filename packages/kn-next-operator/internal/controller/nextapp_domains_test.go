@@ -0,0 +1,149 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/pkg/apis"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("domainsWiring", func() {
+	It("should pass the domain hosts to the node server", func() {
+		w := domainsWiring([]appsv1alpha1.DomainSpec{
+			{Host: "shop.example.com"},
+			{Host: "shop.example.de", Locale: "de"},
+		})
+		Expect(envValue(w.env, "KN_DOMAINS")).To(Equal("shop.example.com,shop.example.de"))
+	})
+
+	It("should leave KN_DOMAINS unset without domains", func() {
+		Expect(domainsWiring(nil).env).To(BeEmpty())
+	})
+})
+
+var _ = Describe("domainTLSSecret", func() {
+	It("should serve plain HTTP without TLS", func() {
+		Expect(domainTLSSecret(appsv1alpha1.DomainSpec{Host: "shop.example.com"})).To(BeEmpty())
+	})
+
+	It("should default the secret name from the host", func() {
+		domain := appsv1alpha1.DomainSpec{
+			Host: "shop.example.com",
+			TLS:  &appsv1alpha1.DomainTLSSpec{IssuerRef: &appsv1alpha1.IssuerRef{Name: "letsencrypt"}},
+		}
+		Expect(domainTLSSecret(domain)).To(Equal("shop.example.com-tls"))
+	})
+
+	It("should use an explicit secret name", func() {
+		domain := appsv1alpha1.DomainSpec{
+			Host: "shop.example.com",
+			TLS:  &appsv1alpha1.DomainTLSSpec{SecretName: "wildcard-example-com"},
+		}
+		Expect(domainTLSSecret(domain)).To(Equal("wildcard-example-com"))
+	})
+})
+
+var _ = Describe("domainStatus", func() {
+	var (
+		domain appsv1alpha1.DomainSpec
+		dm     *servingv1beta1.DomainMapping
+	)
+
+	BeforeEach(func() {
+		domain = appsv1alpha1.DomainSpec{Host: "shop.example.de", Locale: "de"}
+		dm = &servingv1beta1.DomainMapping{ObjectMeta: metav1.ObjectMeta{Name: "shop.example.de", Generation: 1}}
+		dm.Status.ObservedGeneration = 1
+	})
+
+	It("should report a ready domain", func() {
+		dm.Status.URL = apis.HTTPS("shop.example.de")
+		dm.Status.SetConditions(apis.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}})
+		status := domainStatus(domain, dm)
+		Expect(status.Ready).To(Equal(metav1.ConditionTrue))
+		Expect(status.URL).To(Equal("https://shop.example.de"))
+		Expect(status.Locale).To(Equal("de"))
+		Expect(status.Message).To(BeEmpty())
+	})
+
+	It("should surface why a domain is not ready", func() {
+		dm.Status.SetConditions(apis.Conditions{{
+			Type:    apis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Message: "domain name is not claimed",
+		}})
+		status := domainStatus(domain, dm)
+		Expect(status.Ready).To(Equal(metav1.ConditionFalse))
+		Expect(status.Message).To(Equal("domain name is not claimed"))
+	})
+
+	It("should stay unknown until Knative observes the mapping", func() {
+		dm.Generation = 2
+		Expect(domainStatus(domain, dm).Ready).To(Equal(metav1.ConditionUnknown))
+	})
+})
+
+var _ = Describe("deleteCertificate", func() {
+	var (
+		reconciler *NextAppReconciler
+		nextApp    *appsv1alpha1.NextApp
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		certGVK := schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+		scheme.AddKnownTypeWithName(certGVK, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(certGVK.GroupVersion().WithKind("CertificateList"), &unstructured.UnstructuredList{})
+		nextApp = &appsv1alpha1.NextApp{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "store", UID: "shop-uid"}}
+		reconciler = &NextAppReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme: scheme,
+		}
+	})
+
+	It("should delete the Certificate the NextApp requested once its issuer is removed", func() {
+		cert := newCertificate("shop.example.com", "store")
+		Expect(ctrl.SetControllerReference(nextApp, cert, reconciler.Scheme)).To(Succeed())
+		Expect(reconciler.Create(ctx, cert)).To(Succeed())
+
+		Expect(reconciler.deleteCertificate(ctx, nextApp, "shop.example.com")).To(Succeed())
+		err := reconciler.Get(ctx, client.ObjectKey{Name: "shop.example.com", Namespace: "store"}, newCertificate("", ""))
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should keep a Certificate created by someone else", func() {
+		Expect(reconciler.Create(ctx, newCertificate("shop.example.com", "store"))).To(Succeed())
+
+		Expect(reconciler.deleteCertificate(ctx, nextApp, "shop.example.com")).To(Succeed())
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "shop.example.com", Namespace: "store"}, newCertificate("", ""))).To(Succeed())
+	})
+})
@@ -0,0 +1,284 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/value"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+const conditionDriftDetected = "DriftDetected"
+
+// driftReport collects the hand-edited fields of the owned objects applied
+// during one reconcile.
+type driftReport struct {
+	objects []string
+}
+
+func (d *driftReport) add(kind, name string, fields []fieldpath.Path) {
+	d.objects = append(d.objects, fmt.Sprintf("%s %s (%s)", kind, name, fieldList(fields)))
+}
+
+func (d *driftReport) String() string {
+	return strings.Join(d.objects, "; ")
+}
+
+// fieldList formats paths the way kubectl explain names fields.
+func fieldList(paths []fieldpath.Path) string {
+	fields := make([]string, len(paths))
+	for i, path := range paths {
+		fields[i] = strings.TrimPrefix(path.String(), ".")
+	}
+	return strings.Join(fields, ", ")
+}
+
+// driftedFields returns the fields of desired that were changed on live by a
+// manager updating the object directly, such as kubectl edit, sorted by path.
+// An Update takes the ownership of the fields it changes away from the
+// operator, so fields the operator still owns are never reported and spec
+// changes that have not been applied yet are not mistaken for drift.
+// Conflicting server-side appliers are reported by apply instead, and edits
+// that only delete a field cannot be told apart from a new spec field.
+func driftedFields(desired, live *unstructured.Unstructured) ([]fieldpath.Path, error) {
+	var drifted []fieldpath.Path
+	for _, entry := range live.GetManagedFields() {
+		if !editedDirectly(entry) {
+			continue
+		}
+		owned, err := ownedFields(entry)
+		if err != nil {
+			return nil, err
+		}
+		owned.Leaves().Iterate(func(path fieldpath.Path) {
+			want, ok := lookupField(desired.Object, path)
+			if !ok {
+				return
+			}
+			got, ok := lookupField(live.Object, path)
+			if !ok || !containsValue(want, got) {
+				drifted = append(drifted, path.Copy())
+			}
+		})
+	}
+	sort.Slice(drifted, func(i, j int) bool { return drifted[i].String() < drifted[j].String() })
+	return drifted, nil
+}
+
+// releaseFields returns a JSON patch that takes fields away from the managers
+// that changed them with a plain update, so an apply without force can set
+// them back. Fields server-side appliers own are left with them, and a
+// conflict with one of those is still reported by apply.
+func releaseFields(live *unstructured.Unstructured, fields []fieldpath.Path) ([]byte, error) {
+	released := fieldpath.NewSet(fields...)
+	var entries []metav1.ManagedFieldsEntry
+	for _, entry := range live.GetManagedFields() {
+		if !editedDirectly(entry) {
+			entries = append(entries, entry)
+			continue
+		}
+		owned, err := ownedFields(entry)
+		if err != nil {
+			return nil, err
+		}
+		owned = owned.Difference(released)
+		if owned.Empty() {
+			continue
+		}
+		raw, err := owned.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		entry.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+		entries = append(entries, entry)
+	}
+	return json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": live.GetResourceVersion()},
+		{"op": "replace", "path": "/metadata/managedFields", "value": entries},
+	})
+}
+
+// editedDirectly reports whether entry records a plain update of the object
+// by a manager other than the operator.
+func editedDirectly(entry metav1.ManagedFieldsEntry) bool {
+	return entry.Manager != fieldManager && entry.Operation == metav1.ManagedFieldsOperationUpdate &&
+		entry.Subresource == "" && entry.FieldsV1 != nil
+}
+
+func ownedFields(entry metav1.ManagedFieldsEntry) (*fieldpath.Set, error) {
+	owned := &fieldpath.Set{}
+	if err := owned.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+		return nil, fmt.Errorf("parse fields of manager %s: %w", entry.Manager, err)
+	}
+	return owned, nil
+}
+
+// lookupField returns the value at path in an unstructured object.
+func lookupField(obj interface{}, path fieldpath.Path) (interface{}, bool) {
+	for _, element := range path {
+		var ok bool
+		if obj, _, ok = stepField(obj, element); !ok {
+			return nil, false
+		}
+	}
+	return obj, true
+}
+
+// removeField deletes the value at path from an unstructured object, so the
+// operator stops applying it, and returns the object.
+func removeField(obj interface{}, path fieldpath.Path) interface{} {
+	if len(path) == 0 {
+		return obj
+	}
+	child, i, ok := stepField(obj, path[0])
+	if !ok {
+		return obj
+	}
+	switch parent := obj.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(parent, *path[0].FieldName)
+		} else {
+			parent[*path[0].FieldName] = removeField(child, path[1:])
+		}
+	case []interface{}:
+		if len(path) == 1 {
+			return append(parent[:i:i], parent[i+1:]...)
+		}
+		parent[i] = removeField(child, path[1:])
+	}
+	return obj
+}
+
+// stepField resolves one path element, returning the child value and, for
+// lists, its index.
+func stepField(obj interface{}, element fieldpath.PathElement) (interface{}, int, bool) {
+	switch {
+	case element.FieldName != nil:
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, 0, false
+		}
+		child, ok := m[*element.FieldName]
+		return child, 0, ok
+	case element.Index != nil:
+		list, ok := obj.([]interface{})
+		if !ok || *element.Index >= len(list) {
+			return nil, 0, false
+		}
+		return list[*element.Index], *element.Index, true
+	}
+	list, ok := obj.([]interface{})
+	if !ok {
+		return nil, 0, false
+	}
+	for i, item := range list {
+		switch {
+		case element.Value != nil:
+			if value.Equals(value.NewValueInterface(item), *element.Value) {
+				return item, i, true
+			}
+		case element.Key != nil:
+			m, ok := item.(map[string]interface{})
+			if ok && matchesKey(m, *element.Key) {
+				return item, i, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+func matchesKey(item map[string]interface{}, key value.FieldList) bool {
+	for _, field := range key {
+		v, ok := item[field.Name]
+		if !ok || !value.Equals(value.NewValueInterface(v), field.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsValue reports whether got holds every field of want, ignoring the
+// fields the API server or an admission webhook defaulted on top.
+func containsValue(want, got interface{}) bool {
+	switch want := want.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			return len(want) == 0 && got == nil
+		}
+		for k, v := range want {
+			if !containsValue(v, got[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok || len(want) != len(got) {
+			return len(want) == 0 && got == nil
+		}
+		for i := range want {
+			if !containsValue(want[i], got[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return got != nil && value.Equals(value.NewValueInterface(want), value.NewValueInterface(got))
+}
+
+// updateDriftStatus reports the drift found while applying the owned objects
+// in the DriftDetected condition. It is True while a Report policy leaves hand
+// edits in place; a correction is kept as a False condition with the reverted
+// fields until drift is found again.
+func updateDriftStatus(nextApp *appsv1alpha1.NextApp, drift *driftReport) {
+	condition := metav1.Condition{
+		Type:               conditionDriftDetected,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoDrift",
+		Message:            "Owned objects match the spec",
+		ObservedGeneration: nextApp.Generation,
+	}
+	switch {
+	case len(drift.objects) == 0:
+		current := meta.FindStatusCondition(nextApp.Status.Conditions, conditionDriftDetected)
+		if current != nil && current.Reason == "DriftCorrected" {
+			return
+		}
+	case nextApp.Spec.DriftPolicy == appsv1alpha1.DriftPolicyReport:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "DriftReported"
+		condition.Message = "Hand edits left in place by driftPolicy Report: " + drift.String()
+	default:
+		condition.Reason = "DriftCorrected"
+		condition.Message = "Reverted hand edits: " + drift.String()
+	}
+	meta.SetStatusCondition(&nextApp.Status.Conditions, condition)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("driftedFields", func() {
+	var desired, live *unstructured.Unstructured
+
+	container := func(image string, extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"name": "app", "image": image}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+	service := func(replicas int64, c map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":        "shop",
+				"annotations": map[string]interface{}{"owner": "platform"},
+			},
+			"spec": map[string]interface{}{
+				"replicas":   replicas,
+				"containers": []interface{}{c},
+			},
+		}}
+	}
+	managedBy := func(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  operation,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+		}
+	}
+	paths := func() []string {
+		fields, err := driftedFields(desired, live)
+		Expect(err).NotTo(HaveOccurred())
+		if len(fields) == 0 {
+			return nil
+		}
+		return []string{fieldList(fields)}
+	}
+
+	BeforeEach(func() {
+		desired = service(2, container("shop:2", nil))
+		live = service(2, container("shop:2", map[string]interface{}{"imagePullPolicy": "IfNotPresent"}))
+	})
+
+	It("should ignore fields the operator still owns, even when the spec changed", func() {
+		desired = service(3, container("shop:3", nil))
+		live.SetManagedFields([]metav1.ManagedFieldsEntry{
+			managedBy(fieldManager, metav1.ManagedFieldsOperationApply,
+				`{"f:spec":{"f:replicas":{},"f:containers":{"k:{\"name\":\"app\"}":{".":{},"f:image":{}}}}}`),
+		})
+		Expect(paths()).To(BeEmpty())
+	})
+
+	It("should report fields changed by a hand edit", func() {
+		unstructured.SetNestedField(live.Object, int64(5), "spec", "replicas")
+		live.Object["spec"].(map[string]interface{})["containers"] = []interface{}{container("shop:debug", nil)}
+		live.SetManagedFields([]metav1.ManagedFieldsEntry{
+			managedBy("kubectl-edit", metav1.ManagedFieldsOperationUpdate,
+				`{"f:spec":{"f:replicas":{},"f:containers":{"k:{\"name\":\"app\"}":{"f:image":{}}}}}`),
+		})
+		Expect(paths()).To(Equal([]string{`spec.containers[name="app"].image, spec.replicas`}))
+	})
+
+	It("should ignore hand edits that match the spec or touch fields the operator does not set", func() {
+		live.SetAnnotations(map[string]string{"owner": "platform", "sidecar.istio.io/inject": "true"})
+		live.SetManagedFields([]metav1.ManagedFieldsEntry{
+			managedBy("kubectl-edit", metav1.ManagedFieldsOperationUpdate,
+				`{"f:metadata":{"f:annotations":{"f:owner":{},"f:sidecar.istio.io/inject":{}}},"f:spec":{"f:replicas":{}}}`),
+			managedBy("other-tool", metav1.ManagedFieldsOperationApply, `{"f:spec":{"f:replicas":{}}}`),
+		})
+		Expect(paths()).To(BeEmpty())
+	})
+
+	It("should compare atomic lists ignoring defaulted fields", func() {
+		live.SetManagedFields([]metav1.ManagedFieldsEntry{
+			managedBy("kubectl-edit", metav1.ManagedFieldsOperationUpdate, `{"f:spec":{"f:containers":{}}}`),
+		})
+		Expect(paths()).To(BeEmpty())
+
+		live.Object["spec"].(map[string]interface{})["containers"] = []interface{}{
+			container("shop:2", nil), container("debug", nil),
+		}
+		Expect(paths()).To(Equal([]string{"spec.containers"}))
+	})
+})
+
+var _ = Describe("removeField", func() {
+	It("should drop a hand-edited field from the applied object", func() {
+		fields, err := driftedFields(
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"env":      []interface{}{map[string]interface{}{"name": "A", "value": "1"}},
+				},
+			}},
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"managedFields": []interface{}{map[string]interface{}{
+					"manager":   "kubectl-edit",
+					"operation": "Update",
+					"fieldsV1":  map[string]interface{}{"f:spec": map[string]interface{}{"f:env": map[string]interface{}{`k:{"name":"A"}`: map[string]interface{}{}}}},
+				}}},
+				"spec": map[string]interface{}{
+					"env": []interface{}{map[string]interface{}{"name": "A", "value": "2"}},
+				},
+			}},
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fieldList(fields)).To(Equal(`spec.env[name="A"]`))
+
+		obj := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+				"env":      []interface{}{map[string]interface{}{"name": "A", "value": "1"}},
+			},
+		}
+		removeField(obj, fields[0])
+		Expect(obj).To(Equal(map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(2), "env": []interface{}{}},
+		}))
+	})
+})
+
+var _ = Describe("updateDriftStatus", func() {
+	var nextApp *appsv1alpha1.NextApp
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{ObjectMeta: metav1.ObjectMeta{Name: "shop", Generation: 2}}
+	})
+
+	reported := func() *driftReport {
+		drift := &driftReport{}
+		drift.add("Service", "shop", nil)
+		return drift
+	}
+
+	It("should report no drift", func() {
+		updateDriftStatus(nextApp, &driftReport{})
+		condition := meta.FindStatusCondition(nextApp.Status.Conditions, conditionDriftDetected)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("NoDrift"))
+	})
+
+	It("should raise the condition while drift is left in place", func() {
+		nextApp.Spec.DriftPolicy = appsv1alpha1.DriftPolicyReport
+		updateDriftStatus(nextApp, reported())
+		condition := meta.FindStatusCondition(nextApp.Status.Conditions, conditionDriftDetected)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("Service shop"))
+
+		updateDriftStatus(nextApp, &driftReport{})
+		Expect(meta.IsStatusConditionFalse(nextApp.Status.Conditions, conditionDriftDetected)).To(BeTrue())
+	})
+
+	It("should keep the last correction until drift is found again", func() {
+		updateDriftStatus(nextApp, reported())
+		updateDriftStatus(nextApp, &driftReport{})
+		condition := meta.FindStatusCondition(nextApp.Status.Conditions, conditionDriftDetected)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("DriftCorrected"))
+	})
+})
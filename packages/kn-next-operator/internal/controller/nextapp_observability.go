@@ -0,0 +1,407 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+const (
+	defaultMetricsPath    = "/metrics"
+	defaultScrapeInterval = "15s"
+)
+
+// Alert threshold defaults.
+const (
+	defaultAlertErrorRatePercent        = 5
+	defaultAlertLatencyP95Milliseconds  = 2000
+	defaultAlertColdStartP95Seconds     = 10
+	defaultAlertRevalidationLagMessages = 1000
+	defaultAlertFor                     = "5m"
+)
+
+func observabilityEnabled(nextApp *appsv1alpha1.NextApp) bool {
+	return nextApp.Spec.Observability != nil && nextApp.Spec.Observability.Enabled
+}
+
+func metricsPath(obs *appsv1alpha1.ObservabilitySpec) string {
+	if obs.MetricsPath != "" {
+		return obs.MetricsPath
+	}
+	return defaultMetricsPath
+}
+
+// prometheusAnnotations returns the pod template scrape annotations.
+func prometheusAnnotations(obs *appsv1alpha1.ObservabilitySpec) map[string]string {
+	return map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "3000",
+		"prometheus.io/path":   metricsPath(obs),
+	}
+}
+
+// metricsService selects the app's revision pods on the app container port.
+// The ServiceMonitor scrapes its endpoints rather than the Knative Service,
+// which would go through queue-proxy, counting every scrape as a request and
+// keeping scale-to-zero apps warm. It is headless, so it takes no cluster IP.
+func metricsService(nextApp *appsv1alpha1.NextApp) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextApp.Name + "-metrics",
+			Namespace: nextApp.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{"serving.knative.dev/service": nextApp.Name},
+			Ports: []corev1.ServicePort{{
+				Name:       "metrics",
+				Port:       appsv1alpha1.ContainerPort,
+				TargetPort: intstr.FromInt32(appsv1alpha1.ContainerPort),
+			}},
+		},
+	}
+}
+
+// serviceMonitorSpec scrapes the metrics port of the app's metricsService.
+func serviceMonitorSpec(nextApp *appsv1alpha1.NextApp) map[string]interface{} {
+	obs := nextApp.Spec.Observability
+	scrapeInterval := defaultScrapeInterval
+	if obs.Prometheus != nil && obs.Prometheus.ScrapeInterval != "" {
+		scrapeInterval = obs.Prometheus.ScrapeInterval
+	}
+	return map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				nextAppLabel:                 nextApp.Name,
+				"apps.kn-next.dev/component": "metrics",
+			},
+		},
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"port":     "metrics",
+				"path":     metricsPath(obs),
+				"interval": scrapeInterval,
+			},
+		},
+	}
+}
+
+// reconcileObservability creates the metrics Service, the ServiceMonitor and,
+// unless disabled, the Grafana dashboard ConfigMap. The ServiceMonitor is
+// built as an Unstructured object to avoid a prometheus-operator dependency
+// and is skipped when its CRD is not installed.
+func (r *NextAppReconciler) reconcileObservability(ctx context.Context, nextApp *appsv1alpha1.NextApp, applied inventory) error {
+	logger := logf.FromContext(ctx)
+	obs := nextApp.Spec.Observability
+
+	svc := metricsService(nextApp)
+	desired := svc.Spec
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Labels = map[string]string{
+			"app":                        nextApp.Name,
+			"generated-by":               "kn-next-operator",
+			nextAppLabel:                 nextApp.Name,
+			"apps.kn-next.dev/component": "metrics",
+		}
+		svc.Spec.ClusterIP = desired.ClusterIP
+		svc.Spec.Selector = desired.Selector
+		svc.Spec.Ports = desired.Ports
+		return ctrl.SetControllerReference(nextApp, svc, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("reconcile metrics Service: %w", err)
+	}
+	applied.add("Service", svc.Name)
+
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+	serviceMonitor.SetName(nextApp.Name + "-metrics")
+	serviceMonitor.SetNamespace(nextApp.Namespace)
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, serviceMonitor, func() error {
+		serviceMonitor.SetLabels(map[string]string{
+			"app":          nextApp.Name,
+			"generated-by": "kn-next-operator",
+			nextAppLabel:   nextApp.Name,
+		})
+		serviceMonitor.Object["spec"] = serviceMonitorSpec(nextApp)
+		return ctrl.SetControllerReference(nextApp, serviceMonitor, r.Scheme)
+	})
+	switch {
+	case meta.IsNoMatchError(err):
+		logger.Info("ServiceMonitor CRD not installed, skipping", "name", serviceMonitor.GetName())
+	case err != nil:
+		return fmt.Errorf("reconcile ServiceMonitor: %w", err)
+	default:
+		applied.add("ServiceMonitor", serviceMonitor.GetName())
+	}
+
+	if obs.Alerts != nil && obs.Alerts.Enabled {
+		if err := r.reconcilePrometheusRule(ctx, nextApp, applied); err != nil {
+			return err
+		}
+	}
+
+	if obs.Grafana != nil && obs.Grafana.Enabled != nil && !*obs.Grafana.Enabled {
+		return nil
+	}
+
+	dashboard, err := dashboardJSON(nextApp.Name)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextApp.Name + "-grafana-dashboard",
+			Namespace: nextApp.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		if cm.Labels == nil {
+			cm.Labels = make(map[string]string)
+		}
+		cm.Labels["app"] = nextApp.Name
+		cm.Labels["generated-by"] = "kn-next-operator"
+		cm.Labels[nextAppLabel] = nextApp.Name
+		// Picked up by the Grafana dashboard sidecar
+		cm.Labels["grafana_dashboard"] = "1"
+		cm.Data = map[string]string{
+			nextApp.Name + "-bytecode.json": dashboard,
+		}
+		return ctrl.SetControllerReference(nextApp, cm, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("reconcile Grafana dashboard ConfigMap: %w", err)
+	}
+	applied.add("ConfigMap", cm.Name)
+	return nil
+}
+
+// reconcilePrometheusRule creates the per-app alerting rules as an
+// Unstructured PrometheusRule, skipping it when the CRD is not installed.
+func (r *NextAppReconciler) reconcilePrometheusRule(ctx context.Context, nextApp *appsv1alpha1.NextApp, applied inventory) error {
+	logger := logf.FromContext(ctx)
+
+	rule := &unstructured.Unstructured{}
+	rule.SetAPIVersion("monitoring.coreos.com/v1")
+	rule.SetKind("PrometheusRule")
+	rule.SetName(nextApp.Name + "-alerts")
+	rule.SetNamespace(nextApp.Namespace)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, rule, func() error {
+		rule.SetLabels(map[string]string{
+			"app":          nextApp.Name,
+			"generated-by": "kn-next-operator",
+			nextAppLabel:   nextApp.Name,
+		})
+		rule.Object["spec"] = map[string]interface{}{
+			"groups": []interface{}{
+				map[string]interface{}{
+					"name":  nextApp.Name + ".rules",
+					"rules": alertRules(nextApp),
+				},
+			},
+		}
+		return ctrl.SetControllerReference(nextApp, rule, r.Scheme)
+	})
+	if meta.IsNoMatchError(err) {
+		logger.Info("PrometheusRule CRD not installed, skipping", "name", rule.GetName())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reconcile PrometheusRule: %w", err)
+	}
+	applied.add("PrometheusRule", rule.GetName())
+	return nil
+}
+
+// alertRules builds the alerting rules for the app from the Knative
+// queue-proxy request metrics, the kn-next bytecode metrics and, when
+// revalidation goes through Kafka, the consumer group lag.
+func alertRules(nextApp *appsv1alpha1.NextApp) []interface{} {
+	alerts := nextApp.Spec.Observability.Alerts
+	errorRate := int32OrDefault(alerts.ErrorRatePercent, defaultAlertErrorRatePercent)
+	latency := int32OrDefault(alerts.LatencyP95Milliseconds, defaultAlertLatencyP95Milliseconds)
+	coldStart := int32OrDefault(alerts.ColdStartP95Seconds, defaultAlertColdStartP95Seconds)
+	lag := int32OrDefault(alerts.RevalidationLagMessages, defaultAlertRevalidationLagMessages)
+	forDuration := alerts.For
+	if forDuration == "" {
+		forDuration = defaultAlertFor
+	}
+
+	svc := fmt.Sprintf(`namespace_name="%s", service_name="%s"`, nextApp.Namespace, nextApp.Name)
+	rule := func(name, expr, severity, summary string) map[string]interface{} {
+		return map[string]interface{}{
+			"alert": name,
+			"expr":  expr,
+			"for":   forDuration,
+			"labels": map[string]interface{}{
+				"severity": severity,
+				"app":      nextApp.Name,
+			},
+			"annotations": map[string]interface{}{
+				"summary": summary,
+			},
+		}
+	}
+
+	rules := []interface{}{
+		rule("NextAppHighErrorRate",
+			fmt.Sprintf(`sum(rate(revision_app_request_count{%s, response_code_class="5xx"}[5m])) / sum(rate(revision_app_request_count{%s}[5m])) * 100 > %d`,
+				svc, svc, errorRate),
+			"critical",
+			fmt.Sprintf("%s is serving more than %d%% 5xx responses", nextApp.Name, errorRate)),
+		rule("NextAppHighLatency",
+			fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(revision_app_request_latencies_bucket{%s}[5m]))) > %d`,
+				svc, latency),
+			"warning",
+			fmt.Sprintf("%s p95 latency is above %dms", nextApp.Name, latency)),
+		rule("NextAppSlowColdStart",
+			fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(kn_next_startup_duration_seconds_bucket{app="%s"}[15m]))) > %d`,
+				nextApp.Name, coldStart),
+			"warning",
+			fmt.Sprintf("%s p95 cold start is above %ds", nextApp.Name, coldStart)),
+	}
+
+	if nextApp.Spec.Revalidation != nil && nextApp.Spec.Revalidation.Queue == appsv1alpha1.RevalidationQueueKafka {
+		rules = append(rules, rule("NextAppRevalidationLag",
+			fmt.Sprintf(`sum(kafka_consumergroup_lag{consumergroup="%s-revalidation"}) > %d`, nextApp.Name, lag),
+			"warning",
+			fmt.Sprintf("%s revalidation consumer is more than %d messages behind", nextApp.Name, lag)))
+	}
+	return rules
+}
+
+func int32OrDefault(v, def int32) int32 {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+type dashboardTarget struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+}
+
+type dashboardPanel struct {
+	Title       string            `json:"title"`
+	Type        string            `json:"type"`
+	GridPos     map[string]int    `json:"gridPos"`
+	Targets     []dashboardTarget `json:"targets"`
+	FieldConfig map[string]any    `json:"fieldConfig,omitempty"`
+}
+
+// dashboardJSON renders the bytecode cache dashboard, matching
+// generateDashboardJson in the TypeScript generator.
+func dashboardJSON(appName string) (string, error) {
+	sel := fmt.Sprintf(`{app="%s"}`, appName)
+	unit := func(u string) map[string]any {
+		return map[string]any{"defaults": map[string]any{"unit": u}}
+	}
+	pos := func(h, w, x, y int) map[string]int {
+		return map[string]int{"h": h, "w": w, "x": x, "y": y}
+	}
+
+	panels := []dashboardPanel{
+		{
+			Title: "🚀 Cold Start Duration", Type: "timeseries", GridPos: pos(8, 12, 0, 0),
+			Targets: []dashboardTarget{
+				{"histogram_quantile(0.95, rate(kn_next_startup_duration_seconds_bucket" + sel + "[$__rate_interval]))", "p95 ({{cache_status}})"},
+				{"histogram_quantile(0.50, rate(kn_next_startup_duration_seconds_bucket" + sel + "[$__rate_interval]))", "p50 ({{cache_status}})"},
+			},
+			FieldConfig: unit("s"),
+		},
+		{
+			Title: "🔥 Warm vs ❄️ Cold Starts", Type: "piechart", GridPos: pos(8, 6, 12, 0),
+			Targets: []dashboardTarget{
+				{"count(kn_next_bytecode_cache_warm_start" + sel + " == 1)", "warm"},
+				{"count(kn_next_bytecode_cache_warm_start" + sel + " == 0)", "cold"},
+			},
+		},
+		{
+			Title: "📁 Cached Files", Type: "stat", GridPos: pos(4, 6, 0, 8),
+			Targets: []dashboardTarget{{"kn_next_bytecode_cache_files_total" + sel, "{{build_id}}"}},
+		},
+		{
+			Title: "💾 Cache Size", Type: "stat", GridPos: pos(4, 6, 6, 8),
+			Targets:     []dashboardTarget{{"kn_next_bytecode_cache_size_bytes" + sel, "{{build_id}}"}},
+			FieldConfig: unit("bytes"),
+		},
+		{
+			Title: "✏️ Cache Writes", Type: "timeseries", GridPos: pos(4, 6, 12, 8),
+			Targets:     []dashboardTarget{{"rate(kn_next_bytecode_cache_write_count" + sel + "[$__rate_interval])", "writes/s"}},
+			FieldConfig: unit("ops"),
+		},
+		{
+			Title: "🧠 Memory (RSS)", Type: "timeseries", GridPos: pos(6, 12, 0, 12),
+			Targets:     []dashboardTarget{{"process_resident_memory_bytes" + sel, "RSS - {{pod}}"}},
+			FieldConfig: unit("bytes"),
+		},
+		{
+			Title: "⚡ Event Loop Lag", Type: "timeseries", GridPos: pos(6, 12, 12, 12),
+			Targets:     []dashboardTarget{{"nodejs_eventloop_lag_seconds" + sel, "{{pod}}"}},
+			FieldConfig: unit("s"),
+		},
+		{
+			Title: "💻 CPU Usage", Type: "timeseries", GridPos: pos(6, 8, 0, 18),
+			Targets: []dashboardTarget{
+				{"rate(process_cpu_user_seconds_total" + sel + "[$__rate_interval])", "User - {{pod}}"},
+				{"rate(process_cpu_system_seconds_total" + sel + "[$__rate_interval])", "System - {{pod}}"},
+			},
+			FieldConfig: unit("percentunit"),
+		},
+		{
+			Title: "🚦 Active Requests", Type: "timeseries", GridPos: pos(6, 8, 8, 18),
+			Targets: []dashboardTarget{{"nodejs_active_requests" + sel, "Requests - {{pod}}"}},
+		},
+		{
+			Title: "🎛️ Active Handles", Type: "timeseries", GridPos: pos(6, 8, 16, 18),
+			Targets: []dashboardTarget{{"nodejs_active_handles" + sel, "Handles - {{pod}}"}},
+		},
+	}
+
+	dashboard := map[string]any{
+		"annotations":   map[string]any{"list": []any{}},
+		"editable":      true,
+		"panels":        panels,
+		"refresh":       "10s",
+		"schemaVersion": 39,
+		"tags":          []string{"kn-next", "bytecode", appName},
+		"title":         fmt.Sprintf("kn-next: %s Bytecode Cache", appName),
+		"uid":           "kn-next-" + appName,
+		"version":       1,
+	}
+	out, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("render Grafana dashboard: %w", err)
+	}
+	return string(out), nil
+}
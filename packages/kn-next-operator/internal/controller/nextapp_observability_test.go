@@ -0,0 +1,97 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("Observability", func() {
+	It("should default the scrape path to the node server metrics endpoint", func() {
+		annotations := prometheusAnnotations(&appsv1alpha1.ObservabilitySpec{Enabled: true})
+		Expect(annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+		Expect(annotations).To(HaveKeyWithValue("prometheus.io/port", "3000"))
+		Expect(annotations).To(HaveKeyWithValue("prometheus.io/path", "/metrics"))
+	})
+
+	It("should scrape the app container's port on the revision pods", func() {
+		nextApp := &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "store"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Observability: &appsv1alpha1.ObservabilitySpec{Enabled: true, MetricsPath: "/api/metrics"},
+			},
+		}
+		svc := metricsService(nextApp)
+		Expect(svc.Name).To(Equal("shop-metrics"))
+		Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{"serving.knative.dev/service": "shop"}))
+		Expect(svc.Spec.Ports).To(ConsistOf(corev1.ServicePort{
+			Name: "metrics", Port: 3000, TargetPort: intstr.FromInt32(3000),
+		}))
+
+		spec := serviceMonitorSpec(nextApp)
+		Expect(spec["selector"]).To(HaveKeyWithValue("matchLabels", Equal(map[string]interface{}{
+			"apps.kn-next.dev/nextapp":   "shop",
+			"apps.kn-next.dev/component": "metrics",
+		})))
+		endpoint := spec["endpoints"].([]interface{})[0].(map[string]interface{})
+		Expect(endpoint).To(HaveKeyWithValue("port", "metrics"))
+		Expect(endpoint).To(HaveKeyWithValue("path", "/api/metrics"))
+		Expect(endpoint).To(HaveKeyWithValue("interval", "15s"))
+	})
+
+	It("should render a dashboard scoped to the app", func() {
+		out, err := dashboardJSON("shop")
+		Expect(err).NotTo(HaveOccurred())
+
+		var dashboard map[string]any
+		Expect(json.Unmarshal([]byte(out), &dashboard)).To(Succeed())
+		Expect(dashboard["uid"]).To(Equal("kn-next-shop"))
+		Expect(dashboard["panels"]).To(HaveLen(10))
+		Expect(out).To(ContainSubstring(`kn_next_bytecode_cache_size_bytes{app=\"shop\"}`))
+	})
+	It("should generate alert rules with overridden thresholds", func() {
+		nextApp := &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Observability: &appsv1alpha1.ObservabilitySpec{
+					Enabled: true,
+					Alerts:  &appsv1alpha1.AlertsSpec{Enabled: true, ErrorRatePercent: 2},
+				},
+			},
+		}
+		rules := alertRules(nextApp)
+		Expect(rules).To(HaveLen(3))
+		errorRule := rules[0].(map[string]interface{})
+		Expect(errorRule["alert"]).To(Equal("NextAppHighErrorRate"))
+		Expect(errorRule["expr"]).To(HaveSuffix("* 100 > 2"))
+		Expect(errorRule["for"]).To(Equal("5m"))
+
+		nextApp.Spec.Revalidation = &appsv1alpha1.RevalidationSpec{Queue: "kafka"}
+		rules = alertRules(nextApp)
+		Expect(rules).To(HaveLen(4))
+		Expect(rules[3].(map[string]interface{})["expr"]).To(ContainSubstring(`consumergroup="shop-revalidation"`))
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var (
+	kafkaSourceGVK    = schema.GroupVersionKind{Group: "sources.knative.dev", Version: "v1beta1", Kind: "KafkaSource"}
+	triggerGVK        = schema.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1", Kind: "Trigger"}
+	kafkaTopicGVK     = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaTopic"}
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	podMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+)
+
+// prunableKinds are the owned kinds whose objects come and go with spec
+// features. DomainMappings and Certificates are pruned by reconcileDomains.
+var prunableKinds = []schema.GroupVersionKind{
+	corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"),
+	corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+	corev1.SchemeGroupVersion.WithKind("Service"),
+	servingv1.SchemeGroupVersion.WithKind("Service"),
+	kafkaSourceGVK,
+	triggerGVK,
+	kafkaTopicGVK,
+	serviceMonitorGVK,
+	// Operator releases before the metrics Service scraped with a PodMonitor
+	podMonitorGVK,
+	prometheusRuleGVK,
+}
+
+// inventory records the owned objects applied during one reconcile, keyed by
+// kind and name. Whatever reconcilePrune finds that is not in it was left
+// behind by a spec feature that was turned off.
+type inventory map[string]bool
+
+func (inv inventory) add(kind, name string) {
+	inv[kind+"/"+name] = true
+}
+
+func (inv inventory) has(kind, name string) bool {
+	return inv[kind+"/"+name]
+}
+
+// reconcilePrune deletes the objects controlled by the NextApp that were not
+// applied in this reconcile, such as the bytecode cache PVC once
+// enableBytecodeCache is turned off or the KafkaSource once revalidation is
+// removed, and revalidation KafkaTopics in other namespaces the spec no
+// longer points at. Objects are matched by their controller reference rather
+// than nextAppLabel, which objects from older operator releases lack. Kinds
+// whose CRDs are not installed are skipped.
+func (r *NextAppReconciler) reconcilePrune(ctx context.Context, nextApp *appsv1alpha1.NextApp, applied inventory) error {
+	logger := logf.FromContext(ctx)
+
+	for _, gvk := range prunableKinds {
+		var owned metav1.PartialObjectMetadataList
+		owned.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := r.List(ctx, &owned, client.InNamespace(nextApp.Namespace))
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("list %s: %w", gvk.Kind, err)
+		}
+		for i := range owned.Items {
+			obj := &owned.Items[i]
+			if applied.has(gvk.Kind, obj.Name) || !metav1.IsControlledBy(obj, nextApp) || !obj.DeletionTimestamp.IsZero() {
+				continue
+			}
+			obj.SetGroupVersionKind(gvk)
+			if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("prune %s %s: %w", gvk.Kind, obj.Name, err)
+			}
+			logger.Info("Pruned object no longer in spec", "kind", gvk.Kind, "name", obj.Name)
+		}
+	}
+	return r.deleteForeignKafkaTopics(ctx, nextApp, strimziTopicKey(nextApp))
+}
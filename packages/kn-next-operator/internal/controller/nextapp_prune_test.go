@@ -0,0 +1,100 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("reconcilePrune", func() {
+	var (
+		nextApp    *appsv1alpha1.NextApp
+		reconciler *NextAppReconciler
+		scheme     *runtime.Scheme
+	)
+
+	configMap := func(name string, labels map[string]string, owned bool) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+		if owned {
+			Expect(controllerutil.SetControllerReference(nextApp, cm, scheme)).To(Succeed())
+		}
+		return cm
+	}
+	exists := func(name string) bool {
+		err := reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, &corev1.ConfigMap{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default", UID: "shop-uid"}}
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	build := func(objs ...client.Object) {
+		reconciler = &NextAppReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			Scheme: scheme,
+		}
+	}
+
+	It("should delete owned objects that were not applied in this reconcile", func() {
+		build(configMap("shop-grafana-dashboard", map[string]string{nextAppLabel: "shop"}, true))
+
+		Expect(reconciler.reconcilePrune(ctx, nextApp, inventory{})).To(Succeed())
+		Expect(exists("shop-grafana-dashboard")).To(BeFalse())
+	})
+
+	It("should keep the objects that were applied", func() {
+		build(configMap("shop-grafana-dashboard", map[string]string{nextAppLabel: "shop"}, true))
+		applied := inventory{}
+		applied.add("ConfigMap", "shop-grafana-dashboard")
+
+		Expect(reconciler.reconcilePrune(ctx, nextApp, applied)).To(Succeed())
+		Expect(exists("shop-grafana-dashboard")).To(BeTrue())
+	})
+
+	It("should delete owned objects created before the NextApp label", func() {
+		build(configMap("shop-grafana-dashboard", map[string]string{"app": "shop"}, true))
+
+		Expect(reconciler.reconcilePrune(ctx, nextApp, inventory{})).To(Succeed())
+		Expect(exists("shop-grafana-dashboard")).To(BeFalse())
+	})
+
+	It("should leave objects the NextApp does not control", func() {
+		build(configMap("shop-settings", map[string]string{nextAppLabel: "shop"}, false))
+
+		Expect(reconciler.reconcilePrune(ctx, nextApp, inventory{})).To(Succeed())
+		Expect(exists("shop-settings")).To(BeTrue())
+	})
+})
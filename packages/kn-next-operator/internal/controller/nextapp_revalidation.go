@@ -0,0 +1,617 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+	"github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/internal/deadletter"
+)
+
+const (
+	conditionRevalidationReady = "RevalidationReady"
+
+	defaultRevalidationPath = "/api/revalidate"
+	revalidationEventType   = "dev.kn-next.revalidation"
+	brokerRetryInterval     = 30 * time.Second
+	defaultTopicPartitions  = 3
+	defaultTopicReplicas    = 1
+)
+
+// reconcileRevalidation wires the revalidation queue to its sink: the
+// dedicated <app>-revalidator Knative Service when an image is configured,
+// otherwise the app's own revalidation route. Kafka queues are delivered by a
+// KafkaSource, broker queues by a Trigger on the Broker at brokerURL. The
+// sink's readiness is reported in the RevalidationReady condition.
+func (r *NextAppReconciler) reconcileRevalidation(ctx context.Context, nextApp *appsv1alpha1.NextApp, ksvc *servingv1.Service, brokerURL, topic string, drift *driftReport, applied inventory) error {
+	revalidation := nextApp.Spec.Revalidation
+	if revalidation == nil || revalidation.Queue == "" {
+		meta.RemoveStatusCondition(&nextApp.Status.Conditions, conditionRevalidationReady)
+		return nil
+	}
+
+	var kafkaSource *unstructured.Unstructured
+	sinkService := ksvc
+	if image := revalidatorImage(nextApp); image != "" {
+		revalidator, err := r.reconcileRevalidator(ctx, nextApp, image, applied)
+		if err != nil {
+			return err
+		}
+		sinkService = revalidator
+	}
+
+	switch revalidation.Queue {
+	case appsv1alpha1.RevalidationQueueKafka:
+		if err := r.reconcileKafkaTopic(ctx, nextApp, topic, applied); err != nil {
+			return err
+		}
+		source, err := r.reconcileKafkaSource(ctx, nextApp, topic, drift, applied)
+		if err != nil {
+			return err
+		}
+		kafkaSource = source
+	case appsv1alpha1.RevalidationQueueBroker:
+		if err := r.reconcileTrigger(ctx, nextApp, applied); err != nil {
+			return err
+		}
+	}
+
+	condition := sinkReadyCondition(sinkService, nextApp.Generation)
+	if revalidation.Queue == appsv1alpha1.RevalidationQueueBroker && brokerURL == "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "BrokerNotReady"
+		condition.Message = fmt.Sprintf("Broker %s has no address yet", brokerName(nextApp))
+	} else if kafkaSource != nil && condition.Status == metav1.ConditionTrue {
+		condition = kafkaSourceReadyCondition(kafkaSource, condition)
+	}
+	meta.SetStatusCondition(&nextApp.Status.Conditions, condition)
+	return nil
+}
+
+// reconcileKafkaSource consumes the revalidation topic and delivers its events
+// to the sink. It returns the KafkaSource as stored, so its readiness can be
+// reported.
+func (r *NextAppReconciler) reconcileKafkaSource(ctx context.Context, nextApp *appsv1alpha1.NextApp, topic string, drift *driftReport, applied inventory) (*unstructured.Unstructured, error) {
+	// Use Unstructured to avoid Eventing proto deps
+	kafkaSource := &unstructured.Unstructured{}
+	kafkaSource.SetAPIVersion("sources.knative.dev/v1beta1")
+	kafkaSource.SetKind("KafkaSource")
+	kafkaSource.SetName(nextApp.Name + "-revalidation-source")
+	kafkaSource.SetNamespace(nextApp.Namespace)
+	kafkaSource.SetLabels(map[string]string{
+		"app":          nextApp.Name,
+		"generated-by": "kn-next-operator",
+		nextAppLabel:   nextApp.Name,
+	})
+	spec := map[string]interface{}{
+		"consumerGroup": nextApp.Name + "-revalidation",
+		"bootstrapServers": []interface{}{
+			nextApp.Spec.Revalidation.KafkaBrokerUrl,
+		},
+		"topics": []interface{}{
+			topic,
+		},
+		"sink": revalidationSink(nextApp),
+	}
+	if net := kafkaNet(nextApp.Spec.Revalidation.Auth); net != nil {
+		spec["net"] = net
+	}
+	if delivery := deliverySpec(nextApp.Spec.Revalidation.Delivery, r.deadLetterSinkURI(nextApp)); delivery != nil {
+		spec["delivery"] = delivery
+	}
+	kafkaSource.Object["spec"] = spec
+	if err := ctrl.SetControllerReference(nextApp, kafkaSource, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.apply(ctx, nextApp, drift, kafkaSource); err != nil {
+		return nil, fmt.Errorf("reconcile KafkaSource: %w", err)
+	}
+	applied.add("KafkaSource", kafkaSource.GetName())
+	return kafkaSource, nil
+}
+
+// reconcileTrigger subscribes the sink to the app's revalidation events on
+// the Broker.
+func (r *NextAppReconciler) reconcileTrigger(ctx context.Context, nextApp *appsv1alpha1.NextApp, applied inventory) error {
+	trigger := &unstructured.Unstructured{}
+	trigger.SetAPIVersion("eventing.knative.dev/v1")
+	trigger.SetKind("Trigger")
+	trigger.SetName(nextApp.Name + "-revalidation")
+	trigger.SetNamespace(nextApp.Namespace)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, trigger, func() error {
+		trigger.SetLabels(map[string]string{
+			"app":          nextApp.Name,
+			"generated-by": "kn-next-operator",
+			nextAppLabel:   nextApp.Name,
+		})
+		spec := map[string]interface{}{
+			"broker": brokerName(nextApp),
+			"filter": map[string]interface{}{
+				"attributes": map[string]interface{}{
+					"type":   revalidationEventType,
+					"source": revalidationEventSource(nextApp),
+				},
+			},
+			"subscriber": revalidationSink(nextApp),
+		}
+		if delivery := deliverySpec(nextApp.Spec.Revalidation.Delivery, r.deadLetterSinkURI(nextApp)); delivery != nil {
+			spec["delivery"] = delivery
+		}
+		trigger.Object["spec"] = spec
+		return ctrl.SetControllerReference(nextApp, trigger, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("reconcile Trigger: %w", err)
+	}
+	applied.add("Trigger", trigger.GetName())
+	return nil
+}
+
+// deadLetterSinkURI returns where revalidation events that exhausted their
+// retries go: the configured sink, or the operator's dead-letter receiver when
+// one is running.
+func (r *NextAppReconciler) deadLetterSinkURI(nextApp *appsv1alpha1.NextApp) string {
+	delivery := nextApp.Spec.Revalidation.Delivery
+	if delivery == nil {
+		return ""
+	}
+	if delivery.DeadLetterSinkURI != "" {
+		return delivery.DeadLetterSinkURI
+	}
+	if r.DeadLetters != nil && deadletter.UsesReceiver(nextApp) {
+		return r.DeadLetters.SinkURL(nextApp.Namespace, nextApp.Name)
+	}
+	return ""
+}
+
+// deliverySpec builds the Knative delivery section shared by KafkaSource and
+// Trigger, or nil to keep the Eventing defaults.
+func deliverySpec(delivery *appsv1alpha1.DeliverySpec, deadLetterURI string) map[string]interface{} {
+	if delivery == nil {
+		return nil
+	}
+	spec := map[string]interface{}{}
+	if delivery.Retry != nil {
+		spec["retry"] = int64(*delivery.Retry)
+	}
+	if delivery.BackoffPolicy != "" {
+		spec["backoffPolicy"] = delivery.BackoffPolicy
+	}
+	if delivery.BackoffDelay != nil {
+		spec["backoffDelay"] = isoDuration(delivery.BackoffDelay.Duration)
+	}
+	if deadLetterURI != "" {
+		spec["deadLetterSink"] = map[string]interface{}{"uri": deadLetterURI}
+	}
+	if len(spec) == 0 {
+		return nil
+	}
+	return spec
+}
+
+// isoDuration formats d as the ISO 8601 duration Knative expects for
+// backoffDelay, e.g. PT0.2S.
+func isoDuration(d time.Duration) string {
+	return "PT" + strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+}
+
+// brokerURL returns the ingress address of the Broker used by the broker
+// queue, or an empty string while the Broker is missing or not addressable.
+func (r *NextAppReconciler) brokerURL(ctx context.Context, nextApp *appsv1alpha1.NextApp) (string, error) {
+	broker := &unstructured.Unstructured{}
+	broker.SetAPIVersion("eventing.knative.dev/v1")
+	broker.SetKind("Broker")
+	err := r.Get(ctx, types.NamespacedName{Name: brokerName(nextApp), Namespace: nextApp.Namespace}, broker)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get Broker: %w", err)
+	}
+	url, _, err := unstructured.NestedString(broker.Object, "status", "address", "url")
+	if err != nil {
+		return "", fmt.Errorf("read Broker address: %w", err)
+	}
+	return url, nil
+}
+
+func brokerName(nextApp *appsv1alpha1.NextApp) string {
+	if nextApp.Spec.Revalidation.Broker != "" {
+		return nextApp.Spec.Revalidation.Broker
+	}
+	return "default"
+}
+
+// revalidationEventSource is the CloudEvent source the broker queue adapter
+// stamps on revalidation events, so apps sharing a Broker only receive their
+// own.
+func revalidationEventSource(nextApp *appsv1alpha1.NextApp) string {
+	return fmt.Sprintf("/kn-next/isr/%s/%s", nextApp.Namespace, nextApp.Name)
+}
+
+// revalidationWiring returns the env the queue adapters read. The Kafka
+// adapter gets the broker, the topic and, for authenticated brokers, the SASL
+// credentials and TLS material from their Secrets; the broker adapter gets the
+// Broker ingress URL and the event source its Trigger filters on.
+func revalidationWiring(nextApp *appsv1alpha1.NextApp, brokerURL, topic string) podWiring {
+	var w podWiring
+	revalidation := nextApp.Spec.Revalidation
+	if revalidation == nil {
+		return w
+	}
+	if revalidation.Queue == appsv1alpha1.RevalidationQueueBroker {
+		if brokerURL != "" {
+			w.addEnv("REVALIDATION_BROKER_URL", brokerURL)
+		}
+		w.addEnv("REVALIDATION_EVENT_SOURCE", revalidationEventSource(nextApp))
+		return w
+	}
+	if revalidation.Queue != appsv1alpha1.RevalidationQueueKafka {
+		return w
+	}
+	w.addEnv("KAFKA_BROKER_URL", revalidation.KafkaBrokerUrl)
+	w.addEnv("KAFKA_REVALIDATION_TOPIC", topic)
+
+	if revalidation.Auth == nil {
+		return w
+	}
+	if sasl := revalidation.Auth.SASL; sasl != nil {
+		w.addSecretEnv("KAFKA_SASL_MECHANISM", sasl.CredentialsSecret, appsv1alpha1.KafkaSASLTypeKey)
+		w.addSecretEnv("KAFKA_SASL_USERNAME", sasl.CredentialsSecret, appsv1alpha1.KafkaSASLUserKey)
+		w.addSecretEnv("KAFKA_SASL_PASSWORD", sasl.CredentialsSecret, appsv1alpha1.KafkaSASLPasswordKey)
+	}
+	if tls := revalidation.Auth.TLS; tls != nil && tls.Enabled {
+		w.addEnv("KAFKA_TLS", "true")
+		if tls.CASecretRef != nil {
+			w.addSecretEnv("KAFKA_TLS_CA", tls.CASecretRef.Name, secretKeyOrDefault(tls.CASecretRef, "ca.crt"))
+		}
+		if tls.CertSecretRef != nil && tls.KeySecretRef != nil {
+			w.addSecretEnv("KAFKA_TLS_CERT", tls.CertSecretRef.Name, secretKeyOrDefault(tls.CertSecretRef, "tls.crt"))
+			w.addSecretEnv("KAFKA_TLS_KEY", tls.KeySecretRef.Name, secretKeyOrDefault(tls.KeySecretRef, "tls.key"))
+		}
+	}
+	return w
+}
+
+// kafkaNet builds the KafkaSource spec.net section, or nil for a plaintext
+// broker without authentication.
+func kafkaNet(auth *appsv1alpha1.KafkaAuthSpec) map[string]interface{} {
+	if auth == nil {
+		return nil
+	}
+	net := map[string]interface{}{}
+	if sasl := auth.SASL; sasl != nil {
+		net["sasl"] = map[string]interface{}{
+			"enable":   true,
+			"user":     secretValueFrom(sasl.CredentialsSecret, appsv1alpha1.KafkaSASLUserKey),
+			"password": secretValueFrom(sasl.CredentialsSecret, appsv1alpha1.KafkaSASLPasswordKey),
+			"type":     secretValueFrom(sasl.CredentialsSecret, appsv1alpha1.KafkaSASLTypeKey),
+		}
+	}
+	if tls := auth.TLS; tls != nil && tls.Enabled {
+		tlsNet := map[string]interface{}{"enable": true}
+		if tls.CASecretRef != nil {
+			tlsNet["caCert"] = secretValueFrom(tls.CASecretRef.Name, secretKeyOrDefault(tls.CASecretRef, "ca.crt"))
+		}
+		if tls.CertSecretRef != nil && tls.KeySecretRef != nil {
+			tlsNet["cert"] = secretValueFrom(tls.CertSecretRef.Name, secretKeyOrDefault(tls.CertSecretRef, "tls.crt"))
+			tlsNet["key"] = secretValueFrom(tls.KeySecretRef.Name, secretKeyOrDefault(tls.KeySecretRef, "tls.key"))
+		}
+		net["tls"] = tlsNet
+	}
+	if len(net) == 0 {
+		return nil
+	}
+	return net
+}
+
+func secretValueFrom(name, key string) map[string]interface{} {
+	return map[string]interface{}{
+		"secretKeyRef": map[string]interface{}{
+			"name": name,
+			"key":  key,
+		},
+	}
+}
+
+// reconcileKafkaTopic provisions the revalidation topic as a Strimzi
+// KafkaTopic when spec.revalidation.topic.strimzi is set, skipping it when
+// Strimzi is not installed. A topic in another namespace cannot be owned by
+// the NextApp; it is only labelled with it and deleted by
+// deleteForeignKafkaTopics.
+func (r *NextAppReconciler) reconcileKafkaTopic(ctx context.Context, nextApp *appsv1alpha1.NextApp, topic string, applied inventory) error {
+	logger := logf.FromContext(ctx)
+
+	key := strimziTopicKey(nextApp)
+	if key == nil {
+		return nil
+	}
+	namespace, name := key.Namespace, key.Name
+	topicSpec := nextApp.Spec.Revalidation.Topic
+
+	kafkaTopic := &unstructured.Unstructured{}
+	kafkaTopic.SetGroupVersionKind(kafkaTopicGVK)
+	kafkaTopic.SetName(name)
+	kafkaTopic.SetNamespace(namespace)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, kafkaTopic, func() error {
+		kafkaTopic.SetLabels(map[string]string{
+			"app":                 nextApp.Name,
+			"generated-by":        "kn-next-operator",
+			"strimzi.io/cluster":  topicSpec.Strimzi.Cluster,
+			nextAppLabel:          nextApp.Name,
+			nextAppNamespaceLabel: nextApp.Namespace,
+		})
+		kafkaTopic.Object["spec"] = kafkaTopicSpec(nextApp, topic)
+		if namespace != nextApp.Namespace {
+			return nil
+		}
+		return ctrl.SetControllerReference(nextApp, kafkaTopic, r.Scheme)
+	})
+	if meta.IsNoMatchError(err) {
+		logger.Info("Strimzi KafkaTopic CRD not installed, skipping", "name", name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reconcile KafkaTopic: %w", err)
+	}
+	if namespace == nextApp.Namespace {
+		applied.add("KafkaTopic", name)
+	}
+	return nil
+}
+
+// strimziTopicKey returns where the revalidation KafkaTopic lives, or nil when
+// none is provisioned. A topic in another namespace is prefixed with the
+// NextApp's namespace so apps of the same name do not collide.
+func strimziTopicKey(nextApp *appsv1alpha1.NextApp) *types.NamespacedName {
+	revalidation := nextApp.Spec.Revalidation
+	if revalidation == nil || revalidation.Queue != appsv1alpha1.RevalidationQueueKafka ||
+		revalidation.Topic == nil || revalidation.Topic.Strimzi == nil {
+		return nil
+	}
+	key := &types.NamespacedName{Namespace: revalidation.Topic.Strimzi.Namespace, Name: nextApp.Name + "-revalidation"}
+	if key.Namespace == "" {
+		key.Namespace = nextApp.Namespace
+	}
+	if key.Namespace != nextApp.Namespace {
+		key.Name = nextApp.Namespace + "-" + key.Name
+	}
+	return key
+}
+
+// deleteForeignKafkaTopics deletes the revalidation KafkaTopics the NextApp
+// created outside its namespace, which garbage collection cannot reach, except
+// the one at keep.
+func (r *NextAppReconciler) deleteForeignKafkaTopics(ctx context.Context, nextApp *appsv1alpha1.NextApp, keep *types.NamespacedName) error {
+	var topics metav1.PartialObjectMetadataList
+	topics.SetGroupVersionKind(kafkaTopicGVK.GroupVersion().WithKind("KafkaTopicList"))
+	err := r.List(ctx, &topics, client.MatchingLabels{
+		nextAppLabel:          nextApp.Name,
+		nextAppNamespaceLabel: nextApp.Namespace,
+	})
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("list KafkaTopics: %w", err)
+	}
+	for i := range topics.Items {
+		topic := &topics.Items[i]
+		if topic.Namespace == nextApp.Namespace || (keep != nil && client.ObjectKeyFromObject(topic) == *keep) {
+			continue
+		}
+		topic.SetGroupVersionKind(kafkaTopicGVK)
+		if err := r.Delete(ctx, topic); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete KafkaTopic %s/%s: %w", topic.Namespace, topic.Name, err)
+		}
+		logf.FromContext(ctx).Info("Deleted KafkaTopic outside the app namespace", "namespace", topic.Namespace, "name", topic.Name)
+	}
+	return nil
+}
+
+// revalidationTopic returns the Kafka topic revalidation events are published
+// to and consumed from. Apps created before the default followed the
+// TypeScript KafkaQueueConfig keep consuming the <app>-revalidation topic
+// their KafkaSource already reads, so no events are stranded on upgrade.
+func (r *NextAppReconciler) revalidationTopic(ctx context.Context, nextApp *appsv1alpha1.NextApp) (string, error) {
+	if topic := nextApp.Spec.Revalidation.Topic; topic != nil && topic.Name != "" {
+		return topic.Name, nil
+	}
+	legacy := nextApp.Name + "-revalidation"
+	source := &unstructured.Unstructured{}
+	source.SetGroupVersionKind(kafkaSourceGVK)
+	err := r.Get(ctx, types.NamespacedName{Name: nextApp.Name + "-revalidation-source", Namespace: nextApp.Namespace}, source)
+	if err != nil && !meta.IsNoMatchError(err) && !errors.IsNotFound(err) {
+		return "", fmt.Errorf("get KafkaSource: %w", err)
+	}
+	if err == nil {
+		topics, _, _ := unstructured.NestedStringSlice(source.Object, "spec", "topics")
+		if len(topics) == 1 && topics[0] == legacy {
+			return legacy, nil
+		}
+	}
+	return defaultRevalidationTopic(nextApp), nil
+}
+
+// defaultRevalidationTopic matches the TypeScript KafkaQueueConfig default.
+func defaultRevalidationTopic(nextApp *appsv1alpha1.NextApp) string {
+	return nextApp.Name + "-isr-revalidation"
+}
+
+// kafkaTopicSpec builds the Strimzi KafkaTopic spec for the revalidation topic.
+func kafkaTopicSpec(nextApp *appsv1alpha1.NextApp, topicName string) map[string]interface{} {
+	topic := nextApp.Spec.Revalidation.Topic
+	spec := map[string]interface{}{
+		"topicName":  topicName,
+		"partitions": int64(int32OrDefault(topic.Partitions, defaultTopicPartitions)),
+		"replicas":   int64(int32OrDefault(topic.ReplicationFactor, defaultTopicReplicas)),
+	}
+	if topic.Retention != nil {
+		spec["config"] = map[string]interface{}{
+			"retention.ms": topic.Retention.Milliseconds(),
+		}
+	}
+	return spec
+}
+
+// reconcileRevalidator runs the dedicated revalidator image as a Knative
+// Service that forwards revalidation events to the app.
+func (r *NextAppReconciler) reconcileRevalidator(ctx context.Context, nextApp *appsv1alpha1.NextApp, image string, applied inventory) (*servingv1.Service, error) {
+	revalidator := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextApp.Name + "-revalidator",
+			Namespace: nextApp.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, revalidator, func() error {
+		if revalidator.Labels == nil {
+			revalidator.Labels = make(map[string]string)
+		}
+		revalidator.Labels["app"] = nextApp.Name
+		revalidator.Labels["generated-by"] = "kn-next-operator"
+		revalidator.Labels[nextAppLabel] = nextApp.Name
+		revalidator.Labels["apps.kn-next.dev/component"] = "revalidator"
+
+		revalidator.Spec.Template.ObjectMeta.Annotations = map[string]string{
+			"autoscaling.knative.dev/min-scale": "0",
+		}
+		revalidator.Spec.Template.Spec.Containers = []corev1.Container{
+			{
+				Image: image,
+				Env: []corev1.EnvVar{
+					{Name: "TARGET_HOST", Value: fmt.Sprintf("%s.%s.svc.cluster.local", nextApp.Name, nextApp.Namespace)},
+				},
+			},
+		}
+		return ctrl.SetControllerReference(nextApp, revalidator, r.Scheme)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reconcile revalidator Service: %w", err)
+	}
+	applied.add("Service", revalidator.Name)
+	return revalidator, nil
+}
+
+func revalidatorImage(nextApp *appsv1alpha1.NextApp) string {
+	if nextApp.Spec.Revalidation.Revalidator == nil {
+		return ""
+	}
+	return nextApp.Spec.Revalidation.Revalidator.Image
+}
+
+// revalidationSink builds the Knative sink the revalidation events are
+// delivered to.
+func revalidationSink(nextApp *appsv1alpha1.NextApp) map[string]interface{} {
+	if revalidatorImage(nextApp) != "" {
+		return map[string]interface{}{
+			"ref": map[string]interface{}{
+				"apiVersion": "serving.knative.dev/v1",
+				"kind":       "Service",
+				"name":       nextApp.Name + "-revalidator",
+			},
+		}
+	}
+
+	path := defaultRevalidationPath
+	if revalidator := nextApp.Spec.Revalidation.Revalidator; revalidator != nil && revalidator.Path != "" {
+		path = revalidator.Path
+	}
+	return map[string]interface{}{
+		"ref": map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1",
+			"kind":       "Service",
+			"name":       nextApp.Name,
+		},
+		// Resolved relative to the app's URL
+		"uri": path,
+	}
+}
+
+// sinkReadyCondition reports the RevalidationReady condition from the Ready
+// condition of the Knative Service receiving revalidation events.
+func sinkReadyCondition(sink *servingv1.Service, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionRevalidationReady,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "SinkNotReady",
+		Message:            fmt.Sprintf("Waiting for Knative Service %s to become ready", sink.Name),
+		ObservedGeneration: generation,
+	}
+	if sink.IsReady() {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SinkReady"
+		condition.Message = fmt.Sprintf("Revalidation events are delivered to Knative Service %s", sink.Name)
+		return condition
+	}
+	if sink.Status.ObservedGeneration != sink.Generation {
+		return condition
+	}
+	if ready := sink.Status.GetCondition(servingv1.ServiceConditionReady); ready.IsFalse() {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SinkFailed"
+		condition.Message = fmt.Sprintf("Knative Service %s is not ready: %s", sink.Name, ready.Message)
+	}
+	return condition
+}
+
+// kafkaSourceReadyCondition narrows a ready sink's RevalidationReady condition
+// by the Ready condition of the KafkaSource consuming the revalidation topic.
+func kafkaSourceReadyCondition(kafkaSource *unstructured.Unstructured, condition metav1.Condition) metav1.Condition {
+	observed, _, _ := unstructured.NestedInt64(kafkaSource.Object, "status", "observedGeneration")
+	conditions, _, _ := unstructured.NestedSlice(kafkaSource.Object, "status", "conditions")
+	var ready map[string]interface{}
+	if observed == kafkaSource.GetGeneration() {
+		for _, c := range conditions {
+			if c, ok := c.(map[string]interface{}); ok && c["type"] == "Ready" {
+				ready = c
+			}
+		}
+	}
+
+	message, _ := ready["message"].(string)
+	switch ready["status"] {
+	case string(metav1.ConditionTrue):
+		return condition
+	case string(metav1.ConditionFalse):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "KafkaSourceFailed"
+		condition.Message = fmt.Sprintf("KafkaSource %s is not ready: %s", kafkaSource.GetName(), message)
+	default:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "KafkaSourceNotReady"
+		condition.Message = fmt.Sprintf("Waiting for KafkaSource %s to become ready", kafkaSource.GetName())
+	}
+	return condition
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("revalidationSink", func() {
+	var nextApp *appsv1alpha1.NextApp
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Revalidation: &appsv1alpha1.RevalidationSpec{Queue: "kafka", KafkaBrokerUrl: "kafka:9092"},
+			},
+		}
+	})
+
+	It("should deliver to the app's revalidation route by default", func() {
+		sink := revalidationSink(nextApp)
+		Expect(sink).To(HaveKeyWithValue("uri", "/api/revalidate"))
+		Expect(sink["ref"]).To(HaveKeyWithValue("name", "shop"))
+	})
+
+	It("should use a custom revalidation route", func() {
+		nextApp.Spec.Revalidation.Revalidator = &appsv1alpha1.RevalidatorSpec{Path: "/internal/isr"}
+		Expect(revalidationSink(nextApp)).To(HaveKeyWithValue("uri", "/internal/isr"))
+	})
+
+	It("should deliver to the dedicated revalidator when an image is set", func() {
+		nextApp.Spec.Revalidation.Revalidator = &appsv1alpha1.RevalidatorSpec{Image: "registry/shop-revalidator:latest"}
+		sink := revalidationSink(nextApp)
+		Expect(sink).NotTo(HaveKey("uri"))
+		Expect(sink["ref"]).To(HaveKeyWithValue("name", "shop-revalidator"))
+	})
+})
+
+var _ = Describe("sinkReadyCondition", func() {
+	var sink *servingv1.Service
+
+	BeforeEach(func() {
+		sink = &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "shop-revalidator", Generation: 1}}
+		sink.Status.ObservedGeneration = 1
+	})
+
+	It("should be true once the sink is ready", func() {
+		sink.Status.SetConditions(apis.Conditions{{Type: servingv1.ServiceConditionReady, Status: corev1.ConditionTrue}})
+		condition := sinkReadyCondition(sink, 3)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.ObservedGeneration).To(Equal(int64(3)))
+	})
+
+	It("should be false when the sink failed", func() {
+		sink.Status.SetConditions(apis.Conditions{{
+			Type:    servingv1.ServiceConditionReady,
+			Status:  corev1.ConditionFalse,
+			Message: "image pull failed",
+		}})
+		condition := sinkReadyCondition(sink, 1)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("image pull failed"))
+	})
+
+	It("should be unknown while the sink is progressing", func() {
+		sink.Generation = 2
+		Expect(sinkReadyCondition(sink, 1).Status).To(Equal(metav1.ConditionUnknown))
+	})
+})
+
+// eventingReconciler returns a reconciler on a fake client that knows the
+// KafkaSource and Strimzi KafkaTopic kinds.
+func eventingReconciler(objs ...client.Object) *NextAppReconciler {
+	scheme := runtime.NewScheme()
+	Expect(appsv1alpha1.AddToScheme(scheme)).To(Succeed())
+	for _, gvk := range []schema.GroupVersionKind{kafkaSourceGVK, kafkaTopicGVK} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+	return &NextAppReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme: scheme,
+	}
+}
+
+var _ = Describe("revalidationTopic", func() {
+	var nextApp *appsv1alpha1.NextApp
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Revalidation: &appsv1alpha1.RevalidationSpec{Queue: "kafka", KafkaBrokerUrl: "kafka:9092"},
+			},
+		}
+	})
+
+	It("should default to the TypeScript KafkaQueueConfig topic name", func() {
+		Expect(eventingReconciler().revalidationTopic(ctx, nextApp)).To(Equal("shop-isr-revalidation"))
+	})
+
+	It("should use an explicit topic name", func() {
+		nextApp.Spec.Revalidation.Topic = &appsv1alpha1.KafkaTopicSpec{Name: "isr.shop"}
+		Expect(eventingReconciler().revalidationTopic(ctx, nextApp)).To(Equal("isr.shop"))
+	})
+
+	It("should keep the topic an existing KafkaSource consumes under the old default", func() {
+		source := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"topics": []interface{}{"shop-revalidation"}},
+		}}
+		source.SetGroupVersionKind(kafkaSourceGVK)
+		source.SetName("shop-revalidation-source")
+		source.SetNamespace("team-a")
+		Expect(eventingReconciler(source).revalidationTopic(ctx, nextApp)).To(Equal("shop-revalidation"))
+	})
+
+	It("should build the Strimzi KafkaTopic spec", func() {
+		nextApp.Spec.Revalidation.Topic = &appsv1alpha1.KafkaTopicSpec{
+			Partitions:        6,
+			ReplicationFactor: 3,
+			Retention:         &metav1.Duration{Duration: 24 * time.Hour},
+			Strimzi:           &appsv1alpha1.StrimziTopicSpec{Cluster: "events"},
+		}
+		spec := kafkaTopicSpec(nextApp, defaultRevalidationTopic(nextApp))
+		Expect(spec).To(HaveKeyWithValue("topicName", "shop-isr-revalidation"))
+		Expect(spec).To(HaveKeyWithValue("partitions", int64(6)))
+		Expect(spec).To(HaveKeyWithValue("replicas", int64(3)))
+		Expect(spec["config"]).To(HaveKeyWithValue("retention.ms", int64(86400000)))
+	})
+
+	It("should default partitions and replicas and leave retention to the broker", func() {
+		nextApp.Spec.Revalidation.Topic = &appsv1alpha1.KafkaTopicSpec{
+			Strimzi: &appsv1alpha1.StrimziTopicSpec{Cluster: "events"},
+		}
+		spec := kafkaTopicSpec(nextApp, defaultRevalidationTopic(nextApp))
+		Expect(spec).To(HaveKeyWithValue("partitions", int64(3)))
+		Expect(spec).To(HaveKeyWithValue("replicas", int64(1)))
+		Expect(spec).NotTo(HaveKey("config"))
+	})
+})
+
+var _ = Describe("Kafka authentication", func() {
+	var nextApp *appsv1alpha1.NextApp
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Revalidation: &appsv1alpha1.RevalidationSpec{Queue: "kafka", KafkaBrokerUrl: "kafka:9093"},
+			},
+		}
+	})
+
+	It("should leave a plaintext broker without a net section", func() {
+		Expect(kafkaNet(nil)).To(BeNil())
+		wiring := revalidationWiring(nextApp, "", "shop-isr-revalidation")
+		Expect(envValue(wiring.env, "KAFKA_BROKER_URL")).To(Equal("kafka:9093"))
+		Expect(envValue(wiring.env, "KAFKA_REVALIDATION_TOPIC")).To(Equal("shop-isr-revalidation"))
+		Expect(wiring.env).To(HaveLen(2))
+	})
+
+	It("should read SASL credentials from the Secret", func() {
+		nextApp.Spec.Revalidation.Auth = &appsv1alpha1.KafkaAuthSpec{
+			SASL: &appsv1alpha1.KafkaSASLSpec{CredentialsSecret: "kafka-creds"},
+			TLS:  &appsv1alpha1.KafkaTLSSpec{Enabled: true},
+		}
+
+		net := kafkaNet(nextApp.Spec.Revalidation.Auth)
+		sasl := net["sasl"].(map[string]interface{})
+		Expect(sasl).To(HaveKeyWithValue("enable", true))
+		Expect(sasl["type"]).To(Equal(secretValueFrom("kafka-creds", "saslType")))
+		Expect(sasl["user"]).To(Equal(secretValueFrom("kafka-creds", "user")))
+		Expect(net["tls"]).To(Equal(map[string]interface{}{"enable": true}))
+
+		wiring := revalidationWiring(nextApp, "", "shop-isr-revalidation")
+		Expect(envValue(wiring.env, "KAFKA_TLS")).To(Equal("true"))
+		for _, env := range wiring.env {
+			if env.Name == "KAFKA_SASL_PASSWORD" {
+				Expect(env.ValueFrom.SecretKeyRef.Name).To(Equal("kafka-creds"))
+				Expect(env.ValueFrom.SecretKeyRef.Key).To(Equal("password"))
+			}
+		}
+	})
+
+	It("should pass the CA and client certificate for mutual TLS", func() {
+		nextApp.Spec.Revalidation.Auth = &appsv1alpha1.KafkaAuthSpec{
+			TLS: &appsv1alpha1.KafkaTLSSpec{
+				Enabled:       true,
+				CASecretRef:   &appsv1alpha1.SecretKeyRef{Name: "kafka-ca"},
+				CertSecretRef: &appsv1alpha1.SecretKeyRef{Name: "kafka-client"},
+				KeySecretRef:  &appsv1alpha1.SecretKeyRef{Name: "kafka-client", Key: "client.key"},
+			},
+		}
+
+		tls := kafkaNet(nextApp.Spec.Revalidation.Auth)["tls"].(map[string]interface{})
+		Expect(tls["caCert"]).To(Equal(secretValueFrom("kafka-ca", "ca.crt")))
+		Expect(tls["cert"]).To(Equal(secretValueFrom("kafka-client", "tls.crt")))
+		Expect(tls["key"]).To(Equal(secretValueFrom("kafka-client", "client.key")))
+
+		var names []string
+		for _, env := range revalidationWiring(nextApp, "", "shop-isr-revalidation").env {
+			names = append(names, env.Name)
+		}
+		Expect(names).To(ContainElements("KAFKA_TLS_CA", "KAFKA_TLS_CERT", "KAFKA_TLS_KEY"))
+	})
+})
+
+var _ = Describe("Broker revalidation queue", func() {
+	var nextApp *appsv1alpha1.NextApp
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Revalidation: &appsv1alpha1.RevalidationSpec{Queue: "broker"},
+			},
+		}
+	})
+
+	It("should point the app at the Broker ingress", func() {
+		wiring := revalidationWiring(nextApp, "http://broker-ingress.knative-eventing.svc.cluster.local/team-a/default", "")
+		Expect(envValue(wiring.env, "REVALIDATION_BROKER_URL")).To(Equal("http://broker-ingress.knative-eventing.svc.cluster.local/team-a/default"))
+		Expect(envValue(wiring.env, "REVALIDATION_EVENT_SOURCE")).To(Equal("/kn-next/isr/team-a/shop"))
+		Expect(envValue(wiring.env, "KAFKA_BROKER_URL")).To(BeEmpty())
+	})
+
+	It("should omit the ingress until the Broker is addressable", func() {
+		wiring := revalidationWiring(nextApp, "", "shop-isr-revalidation")
+		Expect(wiring.env).To(HaveLen(1))
+		Expect(wiring.env[0].Name).To(Equal("REVALIDATION_EVENT_SOURCE"))
+	})
+
+	It("should default the Broker name", func() {
+		Expect(brokerName(nextApp)).To(Equal("default"))
+		nextApp.Spec.Revalidation.Broker = "rabbitmq"
+		Expect(brokerName(nextApp)).To(Equal("rabbitmq"))
+	})
+})
+
+var _ = Describe("deliverySpec", func() {
+	It("should keep the Eventing defaults without a delivery policy", func() {
+		Expect(deliverySpec(nil, "")).To(BeNil())
+		Expect(deliverySpec(&appsv1alpha1.DeliverySpec{}, "")).To(BeNil())
+	})
+
+	It("should set retries, backoff and the dead-letter sink", func() {
+		delivery := &appsv1alpha1.DeliverySpec{
+			Retry:         ptr.To(int32(5)),
+			BackoffPolicy: "exponential",
+			BackoffDelay:  &metav1.Duration{Duration: 200 * time.Millisecond},
+		}
+		spec := deliverySpec(delivery, "http://dead-letter:8082/team-a/shop")
+		Expect(spec).To(HaveKeyWithValue("retry", int64(5)))
+		Expect(spec).To(HaveKeyWithValue("backoffPolicy", "exponential"))
+		Expect(spec).To(HaveKeyWithValue("backoffDelay", "PT0.2S"))
+		Expect(spec["deadLetterSink"]).To(HaveKeyWithValue("uri", "http://dead-letter:8082/team-a/shop"))
+	})
+
+	It("should format whole-second backoff delays", func() {
+		Expect(isoDuration(2 * time.Second)).To(Equal("PT2S"))
+	})
+
+})
+
+var _ = Describe("deleteForeignKafkaTopics", func() {
+	var nextApp *appsv1alpha1.NextApp
+
+	kafkaTopic := func(namespace, name string) *unstructured.Unstructured {
+		topic := &unstructured.Unstructured{}
+		topic.SetGroupVersionKind(kafkaTopicGVK)
+		topic.SetName(name)
+		topic.SetNamespace(namespace)
+		topic.SetLabels(map[string]string{nextAppLabel: "shop", nextAppNamespaceLabel: "team-a"})
+		return topic
+	}
+	exists := func(r *NextAppReconciler, namespace, name string) bool {
+		return r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, kafkaTopic("", "")) == nil
+	}
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Revalidation: &appsv1alpha1.RevalidationSpec{
+					Queue:          "kafka",
+					KafkaBrokerUrl: "kafka:9092",
+					Topic: &appsv1alpha1.KafkaTopicSpec{
+						Strimzi: &appsv1alpha1.StrimziTopicSpec{Cluster: "events", Namespace: "kafka"},
+					},
+				},
+			},
+		}
+	})
+
+	It("should delete topics left in namespaces the spec no longer points at", func() {
+		r := eventingReconciler(
+			kafkaTopic("kafka", "team-a-shop-revalidation"),
+			kafkaTopic("strimzi", "team-a-shop-revalidation"),
+			kafkaTopic("team-a", "shop-revalidation"),
+		)
+		key := strimziTopicKey(nextApp)
+		Expect(*key).To(Equal(types.NamespacedName{Namespace: "kafka", Name: "team-a-shop-revalidation"}))
+
+		Expect(r.deleteForeignKafkaTopics(ctx, nextApp, key)).To(Succeed())
+		Expect(exists(r, "kafka", "team-a-shop-revalidation")).To(BeTrue())
+		Expect(exists(r, "strimzi", "team-a-shop-revalidation")).To(BeFalse())
+		// Owned, so left to the garbage collector
+		Expect(exists(r, "team-a", "shop-revalidation")).To(BeTrue())
+
+		Expect(r.deleteForeignKafkaTopics(ctx, nextApp, nil)).To(Succeed())
+		Expect(exists(r, "kafka", "team-a-shop-revalidation")).To(BeFalse())
+	})
+})
@@ -0,0 +1,167 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+const (
+	conditionRolledBack = "RolledBack"
+
+	defaultProgressDeadline = 10 * time.Minute
+)
+
+// reconcileRollback checks whether the latest created revision of the Knative
+// Service has failed, either because Knative marked it not ready or because it
+// missed the progress deadline, and records a rollback to the last good
+// revision in nextApp.Status.Rollback. The traffic itself is pinned when the
+// Knative Service is reconciled. It returns how long until the deadline of a
+// still-progressing revision expires (zero when nothing is pending).
+func (r *NextAppReconciler) reconcileRollback(ctx context.Context, nextApp *appsv1alpha1.NextApp) (time.Duration, error) {
+	if nextApp.Spec.Rollback == nil || !nextApp.Spec.Rollback.Enabled {
+		if nextApp.Status.Rollback != nil {
+			nextApp.Status.Rollback = nil
+			meta.SetStatusCondition(&nextApp.Status.Conditions, metav1.Condition{
+				Type:               conditionRolledBack,
+				Status:             metav1.ConditionFalse,
+				Reason:             "RollbackDisabled",
+				Message:            "Automatic rollback was disabled, traffic is routed normally again",
+				ObservedGeneration: nextApp.Generation,
+			})
+		}
+		return 0, nil
+	}
+
+	var ksvc servingv1.Service
+	if err := r.Get(ctx, types.NamespacedName{Name: nextApp.Name, Namespace: nextApp.Namespace}, &ksvc); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	latestCreated := ksvc.Status.LatestCreatedRevisionName
+	latestReady := ksvc.Status.LatestReadyRevisionName
+
+	if rollback := nextApp.Status.Rollback; rollback != nil {
+		if rollback.FailedRevision == latestCreated {
+			// Stay pinned until the spec produces a new revision
+			return 0, nil
+		}
+		nextApp.Status.Rollback = nil
+		meta.SetStatusCondition(&nextApp.Status.Conditions, metav1.Condition{
+			Type:               conditionRolledBack,
+			Status:             metav1.ConditionFalse,
+			Reason:             "NewRevision",
+			Message:            fmt.Sprintf("Revision %s replaced failed revision %s", latestCreated, rollback.FailedRevision),
+			ObservedGeneration: nextApp.Generation,
+		})
+	}
+
+	if latestCreated == "" || latestCreated == latestReady {
+		return 0, nil
+	}
+
+	var rev servingv1.Revision
+	if err := r.Get(ctx, types.NamespacedName{Name: latestCreated, Namespace: nextApp.Namespace}, &rev); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	reason, message, wait := revisionFailure(&rev, progressDeadline(nextApp.Spec.Rollback), time.Now())
+	if reason == "" {
+		return wait, nil
+	}
+
+	target := latestReady
+	if nextApp.Status.Rollout != nil && nextApp.Status.Rollout.StableRevision != "" {
+		target = nextApp.Status.Rollout.StableRevision
+	}
+	image := revisionImage(&rev)
+	nextApp.Status.Rollback = &appsv1alpha1.RollbackStatus{
+		FailedRevision: latestCreated,
+		FailedImage:    image,
+		TargetRevision: target,
+		Time:           metav1.Now(),
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionRolledBack,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            fmt.Sprintf("Revision %s (image %s) failed: %s; traffic pinned to %s", latestCreated, image, message, target),
+		ObservedGeneration: nextApp.Generation,
+	}
+	if target == "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = fmt.Sprintf("Revision %s (image %s) failed: %s; no ready revision to roll back to", latestCreated, image, message)
+	}
+	meta.SetStatusCondition(&nextApp.Status.Conditions, condition)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(nextApp, &rev, corev1.EventTypeWarning, reason, "Rollback", "%s", condition.Message)
+	}
+	return 0, nil
+}
+
+// revisionFailure reports why a revision should be rolled back: reason is
+// "RevisionFailed" when Knative marked it not ready and
+// "ProgressDeadlineExceeded" when it stayed unready past the deadline. When
+// the revision is still progressing, reason is empty and wait is the time left
+// until the deadline.
+func revisionFailure(rev *servingv1.Revision, deadline time.Duration, now time.Time) (reason, message string, wait time.Duration) {
+	ready := rev.Status.GetCondition(servingv1.RevisionConditionReady)
+	if ready.IsTrue() {
+		return "", "", 0
+	}
+	if ready.IsFalse() {
+		return "RevisionFailed", fmt.Sprintf("%s: %s", ready.Reason, ready.Message), 0
+	}
+	age := now.Sub(rev.CreationTimestamp.Time)
+	if age >= deadline {
+		return "ProgressDeadlineExceeded", fmt.Sprintf("not ready after %s", deadline), 0
+	}
+	return "", "", deadline - age
+}
+
+// rollbackTraffic returns the traffic pinned by an active rollback, or nil
+// when traffic is not being held back.
+func rollbackTraffic(nextApp *appsv1alpha1.NextApp) []servingv1.TrafficTarget {
+	if nextApp.Status.Rollback == nil || nextApp.Status.Rollback.TargetRevision == "" {
+		return nil
+	}
+	return pinnedTraffic(nextApp.Status.Rollback.TargetRevision)
+}
+
+func progressDeadline(rollback *appsv1alpha1.RollbackSpec) time.Duration {
+	if rollback.ProgressDeadline != nil && rollback.ProgressDeadline.Duration > 0 {
+		return rollback.ProgressDeadline.Duration
+	}
+	return defaultProgressDeadline
+}
+
+func revisionImage(rev *servingv1.Revision) string {
+	if len(rev.Spec.Containers) == 0 {
+		return ""
+	}
+	return rev.Spec.Containers[0].Image
+}
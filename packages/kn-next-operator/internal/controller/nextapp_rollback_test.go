@@ -0,0 +1,98 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("revisionFailure", func() {
+	var (
+		rev     *servingv1.Revision
+		created time.Time
+	)
+
+	BeforeEach(func() {
+		created = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		rev = &servingv1.Revision{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop-00002", CreationTimestamp: metav1.Time{Time: created}},
+		}
+	})
+
+	It("should wait for the deadline while the revision is progressing", func() {
+		reason, _, wait := revisionFailure(rev, 10*time.Minute, created.Add(4*time.Minute))
+		Expect(reason).To(BeEmpty())
+		Expect(wait).To(Equal(6 * time.Minute))
+	})
+
+	It("should fail a revision that missed the deadline", func() {
+		reason, message, _ := revisionFailure(rev, 10*time.Minute, created.Add(10*time.Minute))
+		Expect(reason).To(Equal("ProgressDeadlineExceeded"))
+		Expect(message).To(ContainSubstring("10m0s"))
+	})
+
+	It("should fail a revision Knative marked not ready", func() {
+		rev.Status.SetConditions(apis.Conditions{{
+			Type:    servingv1.RevisionConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ExitCode1",
+			Message: "Container failed with: boom",
+		}})
+		reason, message, _ := revisionFailure(rev, 10*time.Minute, created.Add(time.Minute))
+		Expect(reason).To(Equal("RevisionFailed"))
+		Expect(message).To(Equal("ExitCode1: Container failed with: boom"))
+	})
+
+	It("should not fail a ready revision", func() {
+		rev.Status.SetConditions(apis.Conditions{{
+			Type:   servingv1.RevisionConditionReady,
+			Status: corev1.ConditionTrue,
+		}})
+		reason, _, wait := revisionFailure(rev, 10*time.Minute, created.Add(time.Hour))
+		Expect(reason).To(BeEmpty())
+		Expect(wait).To(BeZero())
+	})
+})
+
+var _ = Describe("rollbackTraffic", func() {
+	It("should pin traffic to the rollback target", func() {
+		nextApp := &appsv1alpha1.NextApp{Status: appsv1alpha1.NextAppStatus{
+			Rollback: &appsv1alpha1.RollbackStatus{FailedRevision: "shop-00002", TargetRevision: "shop-00001"},
+		}}
+		traffic := rollbackTraffic(nextApp)
+		Expect(traffic).To(HaveLen(1))
+		Expect(traffic[0].RevisionName).To(Equal("shop-00001"))
+		Expect(*traffic[0].Percent).To(Equal(int64(100)))
+	})
+
+	It("should leave traffic alone without a target", func() {
+		nextApp := &appsv1alpha1.NextApp{Status: appsv1alpha1.NextAppStatus{
+			Rollback: &appsv1alpha1.RollbackStatus{FailedRevision: "shop-00001"},
+		}}
+		Expect(rollbackTraffic(nextApp)).To(BeNil())
+		Expect(rollbackTraffic(&appsv1alpha1.NextApp{})).To(BeNil())
+	})
+})
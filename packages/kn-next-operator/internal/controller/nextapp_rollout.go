@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// progressRollout decides the traffic split for a NextApp with a rollout
+// strategy, advancing nextApp.Status.Rollout as steps elapse. It returns the
+// traffic targets for the Knative Service and how long to wait before the
+// next step is due (zero when nothing is pending).
+//
+// The stable revision is always pinned by name, so a new template revision
+// starts at 0% and only receives traffic through the configured steps.
+func progressRollout(nextApp *appsv1alpha1.NextApp, ksvc *servingv1.Service, now time.Time) ([]servingv1.TrafficTarget, time.Duration) {
+	if nextApp.Status.Rollout == nil {
+		nextApp.Status.Rollout = &appsv1alpha1.RolloutStatus{}
+	}
+	status := nextApp.Status.Rollout
+	latestReady := ksvc.Status.LatestReadyRevisionName
+
+	if status.StableRevision == "" {
+		if latestReady == "" {
+			// Nothing is ready yet, let Knative serve the first revision
+			return []servingv1.TrafficTarget{{LatestRevision: ptr.To(true), Percent: ptr.To(int64(100))}}, 0
+		}
+		status.StableRevision = latestReady
+	}
+
+	if latestReady == "" || latestReady == status.StableRevision {
+		clearCanary(status)
+		return pinnedTraffic(status.StableRevision), 0
+	}
+
+	if status.CanaryRevision != latestReady {
+		// A new revision became ready, or superseded the previous canary
+		status.CanaryRevision = latestReady
+		status.CurrentStep = 0
+		status.StepStartTime = &metav1.Time{Time: now}
+	}
+	if status.StepStartTime == nil {
+		status.StepStartTime = &metav1.Time{Time: now}
+	}
+
+	steps := nextApp.Spec.Rollout.Steps
+	for int(status.CurrentStep) < len(steps) {
+		step := steps[status.CurrentStep]
+		if step.Percent >= 100 {
+			break
+		}
+		elapsed := now.Sub(status.StepStartTime.Time)
+		if elapsed < step.Pause.Duration {
+			status.CanaryPercent = step.Percent
+			return splitTraffic(status.StableRevision, status.CanaryRevision, step.Percent), step.Pause.Duration - elapsed
+		}
+		status.CurrentStep++
+		status.StepStartTime = &metav1.Time{Time: now}
+	}
+
+	// All steps done: promote the canary
+	status.StableRevision = status.CanaryRevision
+	clearCanary(status)
+	return pinnedTraffic(status.StableRevision), 0
+}
+
+func clearCanary(status *appsv1alpha1.RolloutStatus) {
+	status.CanaryRevision = ""
+	status.CurrentStep = 0
+	status.CanaryPercent = 0
+	status.StepStartTime = nil
+}
+
+func pinnedTraffic(revision string) []servingv1.TrafficTarget {
+	return []servingv1.TrafficTarget{{RevisionName: revision, Percent: ptr.To(int64(100))}}
+}
+
+func splitTraffic(stable, canary string, canaryPercent int32) []servingv1.TrafficTarget {
+	return []servingv1.TrafficTarget{
+		{RevisionName: stable, Percent: ptr.To(int64(100 - canaryPercent))},
+		{RevisionName: canary, Percent: ptr.To(int64(canaryPercent))},
+	}
+}
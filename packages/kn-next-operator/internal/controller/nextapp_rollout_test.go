@@ -0,0 +1,101 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("progressRollout", func() {
+	var (
+		nextApp *appsv1alpha1.NextApp
+		ksvc    *servingv1.Service
+		start   time.Time
+	)
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			Spec: appsv1alpha1.NextAppSpec{
+				Rollout: &appsv1alpha1.RolloutSpec{
+					Steps: []appsv1alpha1.RolloutStep{
+						{Percent: 10, Pause: metav1.Duration{Duration: 5 * time.Minute}},
+						{Percent: 50, Pause: metav1.Duration{Duration: 10 * time.Minute}},
+					},
+				},
+			},
+		}
+		ksvc = &servingv1.Service{}
+		start = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	It("should pin the first ready revision as stable", func() {
+		ksvc.Status.LatestReadyRevisionName = "shop-00001"
+		traffic, requeue := progressRollout(nextApp, ksvc, start)
+		Expect(requeue).To(BeZero())
+		Expect(traffic).To(HaveLen(1))
+		Expect(traffic[0].RevisionName).To(Equal("shop-00001"))
+		Expect(*traffic[0].Percent).To(Equal(int64(100)))
+		Expect(nextApp.Status.Rollout.StableRevision).To(Equal("shop-00001"))
+	})
+
+	It("should walk a new revision through the steps and promote it", func() {
+		nextApp.Status.Rollout = &appsv1alpha1.RolloutStatus{StableRevision: "shop-00001"}
+		ksvc.Status.LatestReadyRevisionName = "shop-00002"
+
+		traffic, requeue := progressRollout(nextApp, ksvc, start)
+		Expect(requeue).To(Equal(5 * time.Minute))
+		Expect(traffic).To(HaveLen(2))
+		Expect(*traffic[0].Percent).To(Equal(int64(90)))
+		Expect(traffic[1].RevisionName).To(Equal("shop-00002"))
+		Expect(*traffic[1].Percent).To(Equal(int64(10)))
+
+		traffic, requeue = progressRollout(nextApp, ksvc, start.Add(6*time.Minute))
+		Expect(requeue).To(Equal(10 * time.Minute))
+		Expect(*traffic[1].Percent).To(Equal(int64(50)))
+		Expect(nextApp.Status.Rollout.CurrentStep).To(Equal(int32(1)))
+
+		traffic, requeue = progressRollout(nextApp, ksvc, start.Add(17*time.Minute))
+		Expect(requeue).To(BeZero())
+		Expect(traffic).To(HaveLen(1))
+		Expect(traffic[0].RevisionName).To(Equal("shop-00002"))
+		Expect(nextApp.Status.Rollout.StableRevision).To(Equal("shop-00002"))
+		Expect(nextApp.Status.Rollout.CanaryRevision).To(BeEmpty())
+	})
+
+	It("should restart the steps when a newer revision supersedes the canary", func() {
+		nextApp.Status.Rollout = &appsv1alpha1.RolloutStatus{
+			StableRevision: "shop-00001",
+			CanaryRevision: "shop-00002",
+			CurrentStep:    1,
+			StepStartTime:  &metav1.Time{Time: start},
+		}
+		ksvc.Status.LatestReadyRevisionName = "shop-00003"
+
+		traffic, _ := progressRollout(nextApp, ksvc, start.Add(time.Minute))
+		Expect(traffic[0].RevisionName).To(Equal("shop-00001"))
+		Expect(traffic[1].RevisionName).To(Equal("shop-00003"))
+		Expect(*traffic[1].Percent).To(Equal(int64(10)))
+		Expect(nextApp.Status.Rollout.CurrentStep).To(BeZero())
+	})
+})
@@ -0,0 +1,197 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+const (
+	conditionReady        = "Ready"
+	conditionServiceReady = "ServiceReady"
+	conditionCacheReady   = "CacheReady"
+	conditionDegraded     = "Degraded"
+)
+
+// readinessConditions are the conditions Ready aggregates, in the order their
+// failures are reported.
+var readinessConditions = []string{conditionServiceReady, conditionCacheReady, conditionRevalidationReady}
+
+// updateServiceStatus copies the route of the Knative Service into the NextApp
+// status and reports its readiness in the ServiceReady condition.
+func updateServiceStatus(nextApp *appsv1alpha1.NextApp, ksvc *servingv1.Service) {
+	if ksvc.Status.URL != nil {
+		nextApp.Status.URL = ksvc.Status.URL.String()
+	}
+	nextApp.Status.LatestReadyRevision = ksvc.Status.LatestReadyRevisionName
+	nextApp.Status.Traffic = nil
+	for _, target := range ksvc.Status.Traffic {
+		traffic := appsv1alpha1.TrafficStatus{
+			RevisionName:   target.RevisionName,
+			Tag:            target.Tag,
+			LatestRevision: target.LatestRevision != nil && *target.LatestRevision,
+		}
+		if target.Percent != nil {
+			traffic.Percent = *target.Percent
+		}
+		if target.URL != nil {
+			traffic.URL = target.URL.String()
+		}
+		nextApp.Status.Traffic = append(nextApp.Status.Traffic, traffic)
+	}
+	meta.SetStatusCondition(&nextApp.Status.Conditions, serviceReadyCondition(ksvc, nextApp.Generation))
+}
+
+// serviceReadyCondition reports the ServiceReady condition from the Ready
+// condition of the app's Knative Service, keeping Knative's reason.
+func serviceReadyCondition(ksvc *servingv1.Service, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionServiceReady,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "ServiceNotReady",
+		Message:            fmt.Sprintf("Waiting for Knative Service %s to become ready", ksvc.Name),
+		ObservedGeneration: generation,
+	}
+	if ksvc.Status.ObservedGeneration != ksvc.Generation {
+		return condition
+	}
+	ready := ksvc.Status.GetCondition(servingv1.ServiceConditionReady)
+	if ready == nil {
+		return condition
+	}
+	if ready.Reason != "" {
+		condition.Reason = ready.Reason
+	}
+	if ready.Message != "" {
+		condition.Message = fmt.Sprintf("Knative Service %s: %s", ksvc.Name, ready.Message)
+	}
+	switch {
+	case ready.IsTrue():
+		condition.Status = metav1.ConditionTrue
+		if ready.Reason == "" {
+			condition.Reason = "ServiceReady"
+		}
+		condition.Message = fmt.Sprintf("Revision %s is serving", ksvc.Status.LatestReadyRevisionName)
+	case ready.IsFalse():
+		condition.Status = metav1.ConditionFalse
+		if ready.Reason == "" {
+			condition.Reason = "ServiceFailed"
+		}
+	}
+	return condition
+}
+
+// updateCacheStatus reports the CacheReady condition from the phase of the
+// bytecode cache PVC. pvc is nil when the bytecode cache is disabled; the
+// remote cache backends have no readiness of their own to report.
+func updateCacheStatus(nextApp *appsv1alpha1.NextApp, pvc *corev1.PersistentVolumeClaim) {
+	if pvc == nil {
+		meta.RemoveStatusCondition(&nextApp.Status.Conditions, conditionCacheReady)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               conditionCacheReady,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "VolumePending",
+		Message:            fmt.Sprintf("Waiting for PersistentVolumeClaim %s to be bound", pvc.Name),
+		ObservedGeneration: nextApp.Generation,
+	}
+	switch pvc.Status.Phase {
+	case corev1.ClaimBound:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "VolumeBound"
+		condition.Message = fmt.Sprintf("Bytecode cache is stored on PersistentVolumeClaim %s", pvc.Name)
+	case corev1.ClaimLost:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "VolumeLost"
+		condition.Message = fmt.Sprintf("PersistentVolumeClaim %s lost its volume", pvc.Name)
+	}
+	meta.SetStatusCondition(&nextApp.Status.Conditions, condition)
+}
+
+// updateReadyStatus derives the Ready and Degraded conditions from the
+// per-resource conditions. Ready is True only when every reported readiness
+// condition is True; Degraded is True when one of them failed or traffic was
+// rolled back from a failed revision.
+func updateReadyStatus(nextApp *appsv1alpha1.NextApp) {
+	ready := metav1.Condition{
+		Type:               conditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Serving",
+		Message:            "NextApp is serving",
+		ObservedGeneration: nextApp.Generation,
+	}
+	if nextApp.Status.URL != "" {
+		ready.Message = fmt.Sprintf("NextApp is serving at %s", nextApp.Status.URL)
+	}
+	degraded := metav1.Condition{
+		Type:               conditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             "AsExpected",
+		Message:            "No sub-resource has failed",
+		ObservedGeneration: nextApp.Generation,
+	}
+
+	for _, conditionType := range readinessConditions {
+		condition := meta.FindStatusCondition(nextApp.Status.Conditions, conditionType)
+		if condition == nil || condition.Status == metav1.ConditionTrue {
+			continue
+		}
+		// A failure outranks a dependency that is still progressing
+		if ready.Status == metav1.ConditionTrue || (ready.Status == metav1.ConditionUnknown && condition.Status == metav1.ConditionFalse) {
+			ready.Status = condition.Status
+			ready.Reason = condition.Reason
+			ready.Message = fmt.Sprintf("%s: %s", conditionType, condition.Message)
+		}
+		if condition.Status == metav1.ConditionFalse && degraded.Status != metav1.ConditionTrue {
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = condition.Reason
+			degraded.Message = fmt.Sprintf("%s: %s", conditionType, condition.Message)
+		}
+	}
+	if degraded.Status != metav1.ConditionTrue && nextApp.Status.Rollback != nil {
+		if rolledBack := meta.FindStatusCondition(nextApp.Status.Conditions, conditionRolledBack); rolledBack != nil {
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = "RolledBack"
+			degraded.Message = rolledBack.Message
+		}
+	}
+
+	meta.SetStatusCondition(&nextApp.Status.Conditions, ready)
+	meta.SetStatusCondition(&nextApp.Status.Conditions, degraded)
+}
+
+// readyTransition returns the Ready condition of current when its status or
+// reason differs from original, so only transitions are recorded as Events.
+func readyTransition(original, current *appsv1alpha1.NextAppStatus) *metav1.Condition {
+	ready := meta.FindStatusCondition(current.Conditions, conditionReady)
+	if ready == nil {
+		return nil
+	}
+	previous := meta.FindStatusCondition(original.Conditions, conditionReady)
+	if previous != nil && previous.Status == ready.Status && previous.Reason == ready.Reason {
+		return nil
+	}
+	return ready
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("NextApp status", func() {
+	var (
+		nextApp *appsv1alpha1.NextApp
+		ksvc    *servingv1.Service
+	)
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{ObjectMeta: metav1.ObjectMeta{Name: "shop", Generation: 4}}
+		ksvc = &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "shop", Generation: 2}}
+		ksvc.Status.ObservedGeneration = 2
+	})
+
+	conditionStatus := func(conditionType string) metav1.ConditionStatus {
+		condition := meta.FindStatusCondition(nextApp.Status.Conditions, conditionType)
+		Expect(condition).NotTo(BeNil())
+		return condition.Status
+	}
+
+	It("should copy the route and latest ready revision", func() {
+		url, err := apis.ParseURL("https://shop.default.example.com")
+		Expect(err).NotTo(HaveOccurred())
+		ksvc.Status.URL = url
+		ksvc.Status.LatestReadyRevisionName = "shop-00002"
+		ksvc.Status.Traffic = []servingv1.TrafficTarget{
+			{RevisionName: "shop-00001", Percent: ptr.To(int64(80))},
+			{RevisionName: "shop-00002", LatestRevision: ptr.To(true), Percent: ptr.To(int64(20))},
+		}
+
+		updateServiceStatus(nextApp, ksvc)
+		Expect(nextApp.Status.URL).To(Equal("https://shop.default.example.com"))
+		Expect(nextApp.Status.LatestReadyRevision).To(Equal("shop-00002"))
+		Expect(nextApp.Status.Traffic).To(Equal([]appsv1alpha1.TrafficStatus{
+			{RevisionName: "shop-00001", Percent: 80},
+			{RevisionName: "shop-00002", LatestRevision: true, Percent: 20},
+		}))
+	})
+
+	It("should be ready once the Knative Service is ready", func() {
+		ksvc.Status.SetConditions(apis.Conditions{{Type: servingv1.ServiceConditionReady, Status: corev1.ConditionTrue}})
+		updateServiceStatus(nextApp, ksvc)
+		updateCacheStatus(nextApp, nil)
+		updateReadyStatus(nextApp)
+
+		Expect(conditionStatus(conditionServiceReady)).To(Equal(metav1.ConditionTrue))
+		Expect(conditionStatus(conditionReady)).To(Equal(metav1.ConditionTrue))
+		Expect(conditionStatus(conditionDegraded)).To(Equal(metav1.ConditionFalse))
+		Expect(meta.FindStatusCondition(nextApp.Status.Conditions, conditionCacheReady)).To(BeNil())
+	})
+
+	It("should keep Knative's reason when the Knative Service failed", func() {
+		ksvc.Status.SetConditions(apis.Conditions{{
+			Type:    servingv1.ServiceConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  "RevisionFailed",
+			Message: "image pull failed",
+		}})
+		updateServiceStatus(nextApp, ksvc)
+		updateReadyStatus(nextApp)
+
+		ready := meta.FindStatusCondition(nextApp.Status.Conditions, conditionReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("RevisionFailed"))
+		Expect(ready.Message).To(ContainSubstring("image pull failed"))
+		Expect(conditionStatus(conditionDegraded)).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should not be ready while the Knative Service is progressing", func() {
+		ksvc.Generation = 3
+		updateServiceStatus(nextApp, ksvc)
+		updateReadyStatus(nextApp)
+
+		Expect(conditionStatus(conditionReady)).To(Equal(metav1.ConditionUnknown))
+		Expect(conditionStatus(conditionDegraded)).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should report a failure over a dependency that is progressing", func() {
+		updateServiceStatus(nextApp, ksvc)
+		updateCacheStatus(nextApp, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop-bytecode-cache"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimLost},
+		})
+		updateReadyStatus(nextApp)
+
+		ready := meta.FindStatusCondition(nextApp.Status.Conditions, conditionReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("VolumeLost"))
+	})
+
+	It("should be degraded but ready after a rollback", func() {
+		ksvc.Status.SetConditions(apis.Conditions{{Type: servingv1.ServiceConditionReady, Status: corev1.ConditionTrue}})
+		nextApp.Status.Rollback = &appsv1alpha1.RollbackStatus{FailedRevision: "shop-00003", TargetRevision: "shop-00002"}
+		meta.SetStatusCondition(&nextApp.Status.Conditions, metav1.Condition{
+			Type:    conditionRolledBack,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ProgressDeadlineExceeded",
+			Message: "Revision shop-00003 failed",
+		})
+		updateServiceStatus(nextApp, ksvc)
+		updateReadyStatus(nextApp)
+
+		Expect(conditionStatus(conditionReady)).To(Equal(metav1.ConditionTrue))
+		degraded := meta.FindStatusCondition(nextApp.Status.Conditions, conditionDegraded)
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("RolledBack"))
+	})
+})
+
+var _ = Describe("kafkaSourceReadyCondition", func() {
+	var (
+		kafkaSource *unstructured.Unstructured
+		sinkReady   metav1.Condition
+	)
+
+	BeforeEach(func() {
+		kafkaSource = &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "shop-revalidation-source", "generation": int64(1)},
+		}}
+		sinkReady = metav1.Condition{Type: conditionRevalidationReady, Status: metav1.ConditionTrue, Reason: "SinkReady"}
+	})
+
+	setReady := func(status, message string) {
+		Expect(unstructured.SetNestedField(kafkaSource.Object, map[string]interface{}{
+			"observedGeneration": int64(1),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": status, "message": message},
+			},
+		}, "status")).To(Succeed())
+	}
+
+	It("should keep the sink condition once the KafkaSource is ready", func() {
+		setReady("True", "")
+		Expect(kafkaSourceReadyCondition(kafkaSource, sinkReady)).To(Equal(sinkReady))
+	})
+
+	It("should be false when the KafkaSource failed", func() {
+		setReady("False", "topic not found")
+		condition := kafkaSourceReadyCondition(kafkaSource, sinkReady)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("KafkaSourceFailed"))
+		Expect(condition.Message).To(ContainSubstring("topic not found"))
+	})
+
+	It("should be unknown until the KafkaSource reports status", func() {
+		condition := kafkaSourceReadyCondition(kafkaSource, sinkReady)
+		Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(condition.Reason).To(Equal("KafkaSourceNotReady"))
+	})
+})
+
+var _ = Describe("readyTransition", func() {
+	ready := func(status metav1.ConditionStatus, reason string) appsv1alpha1.NextAppStatus {
+		return appsv1alpha1.NextAppStatus{Conditions: []metav1.Condition{
+			{Type: conditionReady, Status: status, Reason: reason, Message: "Revision shop-00002 is serving"},
+		}}
+	}
+
+	It("should report the first Ready condition", func() {
+		current := ready(metav1.ConditionUnknown, "ServiceNotReady")
+		Expect(readyTransition(&appsv1alpha1.NextAppStatus{}, &current)).To(HaveField("Reason", "ServiceNotReady"))
+	})
+
+	It("should report a change of status or reason only", func() {
+		original := ready(metav1.ConditionFalse, "RevisionFailed")
+		current := ready(metav1.ConditionFalse, "RevisionFailed")
+		current.Conditions[0].Message = "Revision shop-00003 failed"
+		Expect(readyTransition(&original, &current)).To(BeNil())
+
+		current = ready(metav1.ConditionFalse, "VolumeLost")
+		Expect(readyTransition(&original, &current)).To(HaveField("Reason", "VolumeLost"))
+
+		current = ready(metav1.ConditionTrue, "Serving")
+		Expect(readyTransition(&original, &current)).To(HaveField("Status", metav1.ConditionTrue))
+	})
+})
@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+const (
+	defaultStorageRegion = "us-east-1"
+
+	// storageCredentialsMountPath is where the GCS service account Secret is mounted.
+	storageCredentialsMountPath = "/var/secrets/storage"
+)
+
+// storageWiring returns the provider-specific env contract consumed by the
+// kn-next storage and incremental cache adapters. It mirrors
+// storageEnvVarGenerators in the TypeScript generator.
+func storageWiring(appName string, storage *appsv1alpha1.StorageSpec) podWiring {
+	var w podWiring
+	if storage == nil || storage.Provider == "" {
+		return w
+	}
+
+	w.addEnv("STORAGE_PROVIDER", storage.Provider)
+	if storage.PublicURL != "" {
+		w.addEnv("ASSET_PREFIX", storage.PublicURL)
+	}
+
+	region := storage.Region
+	if region == "" {
+		region = defaultStorageRegion
+	}
+
+	switch storage.Provider {
+	case appsv1alpha1.StorageProviderGCS:
+		w.addEnv("GCS_BUCKET_NAME", storage.Bucket)
+		w.addEnv("GCS_BUCKET_KEY_PREFIX", appName)
+		if storage.CredentialsSecret != "" {
+			w.addSecretVolume("storage-credentials", storage.CredentialsSecret, storageCredentialsMountPath)
+			w.addEnv("GOOGLE_APPLICATION_CREDENTIALS",
+				storageCredentialsMountPath+"/"+appsv1alpha1.StorageCredentialsGCSKey)
+		}
+	case appsv1alpha1.StorageProviderS3, appsv1alpha1.StorageProviderMinio:
+		w.addEnv("CACHE_BUCKET_NAME", storage.Bucket)
+		w.addEnv("CACHE_BUCKET_KEY_PREFIX", appName)
+		w.addEnv("CACHE_BUCKET_REGION", region)
+		if storage.Endpoint != "" {
+			w.addEnv("S3_ENDPOINT", storage.Endpoint)
+		}
+		if storage.CredentialsSecret != "" {
+			w.addSecretEnv("AWS_ACCESS_KEY_ID", storage.CredentialsSecret, appsv1alpha1.StorageCredentialsAccessKey)
+			w.addSecretEnv("AWS_SECRET_ACCESS_KEY", storage.CredentialsSecret, appsv1alpha1.StorageCredentialsSecretKey)
+		}
+	case appsv1alpha1.StorageProviderAzure:
+		w.addEnv("AZURE_STORAGE_CONTAINER_NAME", storage.Bucket)
+		if storage.Endpoint != "" {
+			w.addEnv("AZURE_STORAGE_ENDPOINT", storage.Endpoint)
+		}
+		if storage.CredentialsSecret != "" {
+			w.addSecretEnv("AZURE_STORAGE_CONNECTION_STRING", storage.CredentialsSecret,
+				appsv1alpha1.StorageCredentialsConnectionStringKey)
+		}
+	}
+	return w
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+func envValue(envVars []corev1.EnvVar, name string) string {
+	for _, env := range envVars {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+var _ = Describe("storageWiring", func() {
+	It("should emit nothing without a provider", func() {
+		Expect(storageWiring("app", nil).env).To(BeEmpty())
+		Expect(storageWiring("app", &appsv1alpha1.StorageSpec{}).env).To(BeEmpty())
+	})
+
+	It("should emit the GCS contract and mount credentials", func() {
+		w := storageWiring("shop", &appsv1alpha1.StorageSpec{
+			Provider:          appsv1alpha1.StorageProviderGCS,
+			Bucket:            "assets",
+			PublicURL:         "https://storage.googleapis.com/assets",
+			CredentialsSecret: "gcs-sa",
+		})
+		Expect(envValue(w.env, "GCS_BUCKET_NAME")).To(Equal("assets"))
+		Expect(envValue(w.env, "GCS_BUCKET_KEY_PREFIX")).To(Equal("shop"))
+		Expect(envValue(w.env, "ASSET_PREFIX")).To(Equal("https://storage.googleapis.com/assets"))
+		Expect(envValue(w.env, "GOOGLE_APPLICATION_CREDENTIALS")).To(Equal("/var/secrets/storage/key.json"))
+		Expect(w.volumes).To(HaveLen(1))
+		Expect(w.volumes[0].Secret.SecretName).To(Equal("gcs-sa"))
+	})
+
+	It("should emit the S3 contract for minio with endpoint and secret credentials", func() {
+		w := storageWiring("shop", &appsv1alpha1.StorageSpec{
+			Provider:          appsv1alpha1.StorageProviderMinio,
+			Bucket:            "assets",
+			Endpoint:          "http://minio.storage:9000",
+			CredentialsSecret: "minio-creds",
+		})
+		Expect(envValue(w.env, "CACHE_BUCKET_NAME")).To(Equal("assets"))
+		Expect(envValue(w.env, "CACHE_BUCKET_KEY_PREFIX")).To(Equal("shop"))
+		Expect(envValue(w.env, "CACHE_BUCKET_REGION")).To(Equal("us-east-1"))
+		Expect(envValue(w.env, "S3_ENDPOINT")).To(Equal("http://minio.storage:9000"))
+		Expect(envValue(w.env, "GCS_BUCKET_NAME")).To(BeEmpty())
+
+		var accessKey *corev1.SecretKeySelector
+		for _, env := range w.env {
+			if env.Name == "AWS_ACCESS_KEY_ID" {
+				accessKey = env.ValueFrom.SecretKeyRef
+			}
+		}
+		Expect(accessKey).NotTo(BeNil())
+		Expect(accessKey.Name).To(Equal("minio-creds"))
+		Expect(accessKey.Key).To(Equal("accessKey"))
+	})
+})
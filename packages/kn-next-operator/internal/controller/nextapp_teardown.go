@@ -0,0 +1,319 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// teardownFinalizer holds a deleted NextApp until its deletion policy has
+// been applied.
+const teardownFinalizer = "apps.kn-next.dev/teardown"
+
+// Images running the provider CLIs the purge Job uses, the same tools
+// packages/kn-next/src/cli/cleanup.ts shells out to.
+const (
+	purgeGCSImage   = "gcr.io/google.com/cloudsdktool/google-cloud-cli:slim"
+	purgeS3Image    = "amazon/aws-cli:2.31.0"
+	purgeRedisImage = "redis:7-alpine"
+
+	purgeBackoffLimit = 3
+	// purgeDeadlineSeconds bounds the whole purge, so a Job that can never
+	// run, such as one whose image cannot be pulled, still fails
+	purgeDeadlineSeconds = 600
+	// purgeTTLSeconds removes a finished Job the operator could not delete
+	purgeTTLSeconds = 3600
+)
+
+// Purge scripts, one per container. They read the env contract of
+// storageWiring and redisWiring, so credentials stay in the app's Secrets.
+const (
+	purgeGCSScript = `if gcloud storage ls "gs://$GCS_BUCKET_NAME/$GCS_BUCKET_KEY_PREFIX/" >/dev/null; then
+  gcloud storage rm --recursive "gs://$GCS_BUCKET_NAME/$GCS_BUCKET_KEY_PREFIX/"
+fi`
+	purgeS3Script = `aws s3 rm "s3://$CACHE_BUCKET_NAME/$CACHE_BUCKET_KEY_PREFIX/" --recursive --region "$CACHE_BUCKET_REGION" ${S3_ENDPOINT:+--endpoint-url "$S3_ENDPOINT"}`
+	// Both Redis adapters join their keys onto REDIS_KEY_PREFIX with a slash
+	purgeRedisScript = `set -o pipefail
+redis-cli -u "$REDIS_URL" ${REDIS_TLS:+--tls} ${NODE_EXTRA_CA_CERTS:+--cacert "$NODE_EXTRA_CA_CERTS"} --scan --pattern "$REDIS_KEY_PREFIX/*" |
+  xargs -r -n 100 redis-cli -u "$REDIS_URL" ${REDIS_TLS:+--tls} ${NODE_EXTRA_CA_CERTS:+--cacert "$NODE_EXTRA_CA_CERTS"} unlink >/dev/null`
+)
+
+// reconcileDelete applies spec.deletionPolicy to a NextApp being deleted and
+// releases the teardown finalizer once it is done. With the Delete policy a
+// purge Job empties the app's storage prefix and Redis keys first; a failed
+// or timed out purge is reported and the NextApp released, so deletion never
+// wedges.
+//
+// Revalidation KafkaTopics outside the namespace are deleted whatever the
+// policy, as the owned objects are, since garbage collection cannot reach
+// them.
+//
+// The Job has no owner reference: a foreground deletion garbage-collects the
+// NextApp's dependents before the finalizer is released, which would delete
+// the Job mid-purge. It is found by its labels and deleted once done. The
+// app's ServiceAccount, which the Job runs under, is released from the
+// NextApp for the same reason and deleted once the purge is over.
+func (r *NextAppReconciler) reconcileDelete(ctx context.Context, nextApp *appsv1alpha1.NextApp) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(nextApp, teardownFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.deleteForeignKafkaTopics(ctx, nextApp, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if nextApp.Spec.DeletionPolicy != appsv1alpha1.DeletionPolicyDelete {
+		r.teardownEvent(nextApp, nil, corev1.EventTypeNormal, "StorageRetained",
+			"Deletion policy Retain: app storage and cache entries were kept")
+		return ctrl.Result{}, r.releaseTeardown(ctx, nextApp)
+	}
+
+	job, targets := purgeJob(nextApp)
+	if job == nil {
+		r.teardownEvent(nextApp, nil, corev1.EventTypeNormal, "StoragePurged",
+			"Deletion policy Delete: no storage or Redis cache is configured, nothing to purge")
+		return ctrl.Result{}, r.releaseTeardown(ctx, nextApp)
+	}
+
+	existing := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, existing)
+	if errors.IsNotFound(err) {
+		if err := r.retainServiceAccount(ctx, nextApp); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			if errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+				r.teardownEvent(nextApp, nil, corev1.EventTypeWarning, "PurgeFailed",
+					"Namespace is terminating, cannot start purge of %s", targets)
+				return ctrl.Result{}, r.releaseTeardown(ctx, nextApp)
+			}
+			return ctrl.Result{}, fmt.Errorf("create purge Job: %w", err)
+		}
+		logger.Info("Purging app storage", "job", job.Name, "targets", targets)
+		r.teardownEvent(nextApp, job, corev1.EventTypeNormal, "PurgeStarted", "Purging %s", targets)
+		// Jobs are watched by label, its completion triggers the next reconcile
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get purge Job: %w", err)
+	}
+
+	switch failed := jobCondition(existing, batchv1.JobFailed); {
+	case jobCondition(existing, batchv1.JobComplete) != nil:
+		r.teardownEvent(nextApp, existing, corev1.EventTypeNormal, "StoragePurged", "Purged %s", targets)
+	case failed != nil && failed.Reason == batchv1.JobReasonDeadlineExceeded:
+		r.teardownEvent(nextApp, existing, corev1.EventTypeWarning, "PurgeFailed",
+			"Purge Job %s did not finish within %ds, %s must be cleaned up manually",
+			existing.Name, purgeDeadlineSeconds, targets)
+	case failed != nil:
+		r.teardownEvent(nextApp, existing, corev1.EventTypeWarning, "PurgeFailed",
+			"Purge Job %s failed, %s must be cleaned up manually", existing.Name, targets)
+	default:
+		return ctrl.Result{}, nil
+	}
+	// Deleted before the finalizer is released, after which nothing else would
+	err = r.Delete(ctx, appServiceAccount(nextApp))
+	if client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("delete ServiceAccount: %w", err)
+	}
+	if err := r.releaseTeardown(ctx, nextApp); err != nil {
+		return ctrl.Result{}, err
+	}
+	// Left for purgeTTLSeconds otherwise, where a NextApp recreated under the
+	// same name would mistake it for its own purge
+	err = r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
+// retainServiceAccount takes the app's ServiceAccount out of the NextApp's
+// garbage collection, so it outlives the NextApp until the purge Job that
+// runs under it is done. One a foreground deletion already collected is
+// recreated.
+func (r *NextAppReconciler) retainServiceAccount(ctx context.Context, nextApp *appsv1alpha1.NextApp) error {
+	sa := appServiceAccount(nextApp)
+	err := r.Get(ctx, client.ObjectKeyFromObject(sa), sa)
+	if errors.IsNotFound(err) {
+		if err := r.Create(ctx, appServiceAccount(nextApp)); client.IgnoreAlreadyExists(err) != nil {
+			return fmt.Errorf("recreate ServiceAccount for the purge: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get ServiceAccount: %w", err)
+	}
+	owned, err := controllerutil.HasOwnerReference(sa.OwnerReferences, nextApp, r.Scheme)
+	if err != nil || !owned {
+		return err
+	}
+	if err := controllerutil.RemoveOwnerReference(nextApp, sa, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, sa); err != nil {
+		return fmt.Errorf("release ServiceAccount from the NextApp: %w", err)
+	}
+	return nil
+}
+
+// releaseTeardown removes the teardown finalizer so the NextApp and its owned
+// objects can be garbage collected.
+func (r *NextAppReconciler) releaseTeardown(ctx context.Context, nextApp *appsv1alpha1.NextApp) error {
+	if controllerutil.RemoveFinalizer(nextApp, teardownFinalizer) {
+		if err := r.Update(ctx, nextApp); err != nil {
+			return fmt.Errorf("remove teardown finalizer: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *NextAppReconciler) teardownEvent(nextApp *appsv1alpha1.NextApp, related runtime.Object, eventType, reason, note string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(nextApp, related, eventType, reason, "Teardown", note, args...)
+	}
+}
+
+// jobCondition returns the condition of the given type when it is True.
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// purgeJobToNextApp maps a purge Job to the NextApp it purges, which no
+// longer exists as its owner.
+func purgeJobToNextApp(_ context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[nextAppLabel]
+	if !ok || obj.GetName() != name+"-purge" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}}}
+}
+
+// purgeJob builds the Job that purges the app's object-storage prefix and
+// Redis cache keys, with one container per target, and describes the targets
+// for Events. It returns nil when there is nothing to purge. The Job runs
+// with the app's storage and cache env under the app's ServiceAccount, so it
+// reaches exactly what the app does.
+func purgeJob(nextApp *appsv1alpha1.NextApp) (*batchv1.Job, string) {
+	var (
+		containers []corev1.Container
+		volumes    []corev1.Volume
+		targets    []string
+	)
+	addContainer := func(name, image, script string, w podWiring) {
+		containers = append(containers, corev1.Container{
+			Name:         name,
+			Image:        image,
+			Command:      []string{"/bin/sh", "-c", script},
+			Env:          w.env,
+			VolumeMounts: w.mounts,
+		})
+		volumes = append(volumes, w.volumes...)
+	}
+
+	if storage := nextApp.Spec.Storage; storage != nil {
+		w := storageWiring(nextApp.Name, storage)
+		switch storage.Provider {
+		case appsv1alpha1.StorageProviderGCS:
+			// gcloud ignores GOOGLE_APPLICATION_CREDENTIALS
+			for _, env := range w.env {
+				if env.Name == "GOOGLE_APPLICATION_CREDENTIALS" {
+					w.addEnv("CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE", env.Value)
+				}
+			}
+			addContainer("purge-storage", purgeGCSImage, purgeGCSScript, w)
+			targets = append(targets, fmt.Sprintf("gs://%s/%s/", storage.Bucket, nextApp.Name))
+		case appsv1alpha1.StorageProviderS3, appsv1alpha1.StorageProviderMinio:
+			addContainer("purge-storage", purgeS3Image, purgeS3Script, w)
+			targets = append(targets, fmt.Sprintf("s3://%s/%s/", storage.Bucket, nextApp.Name))
+		}
+		// Azure apps write to the container root, which other apps may share;
+		// validation refuses the Delete policy for them
+	}
+
+	if redis := redisBackend(nextApp.Spec.Cache); redis != nil {
+		w := redisWiring(redis, nextApp.Namespace+"/"+nextApp.Name)
+		addContainer("purge-redis", purgeRedisImage, purgeRedisScript, w)
+		prefix := redis.KeyPrefix
+		if prefix == "" {
+			prefix = nextApp.Namespace + "/" + nextApp.Name
+		}
+		targets = append(targets, fmt.Sprintf("Redis keys %s/*", prefix))
+	}
+
+	if len(containers) == 0 {
+		return nil, ""
+	}
+	labels := map[string]string{
+		"app":          nextApp.Name,
+		"generated-by": "kn-next-operator",
+		nextAppLabel:   nextApp.Name,
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextApp.Name + "-purge",
+			Namespace: nextApp.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To(int32(purgeBackoffLimit)),
+			ActiveDeadlineSeconds:   ptr.To(int64(purgeDeadlineSeconds)),
+			TTLSecondsAfterFinished: ptr.To(int32(purgeTTLSeconds)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName:           appServiceAccount(nextApp).Name,
+					AutomountServiceAccountToken: ptr.To(false),
+					RestartPolicy:                corev1.RestartPolicyNever,
+					Containers:                   containers,
+					Volumes:                      volumes,
+				},
+			},
+		},
+	}, strings.Join(targets, " and ")
+}
+
+// redisBackend returns the Redis settings shared by the incremental and tag
+// caches, or nil when neither uses Redis.
+func redisBackend(cache *appsv1alpha1.CacheSpec) *appsv1alpha1.RedisCacheSpec {
+	incremental, tag := resolveCacheBackends(cache)
+	for _, backend := range []*appsv1alpha1.CacheBackendSpec{incremental, tag} {
+		if backend != nil && backend.Provider == appsv1alpha1.CacheProviderRedis && backend.Redis != nil {
+			return backend.Redis
+		}
+	}
+	return nil
+}
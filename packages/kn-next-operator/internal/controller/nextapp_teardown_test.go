@@ -0,0 +1,236 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("purgeJob", func() {
+	var nextApp *appsv1alpha1.NextApp
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "store"},
+			Spec:       appsv1alpha1.NextAppSpec{DeletionPolicy: appsv1alpha1.DeletionPolicyDelete},
+		}
+	})
+
+	env := func(container corev1.Container) map[string]string {
+		values := map[string]string{}
+		for _, e := range container.Env {
+			values[e.Name] = e.Value
+		}
+		return values
+	}
+
+	It("should have nothing to purge without storage or Redis", func() {
+		nextApp.Spec.Cache = &appsv1alpha1.CacheSpec{EnableBytecodeCache: true}
+		job, _ := purgeJob(nextApp)
+		Expect(job).To(BeNil())
+	})
+
+	It("should purge the app's GCS prefix", func() {
+		nextApp.Spec.Storage = &appsv1alpha1.StorageSpec{
+			Provider:          appsv1alpha1.StorageProviderGCS,
+			Bucket:            "assets",
+			CredentialsSecret: "gcs-key",
+		}
+		job, targets := purgeJob(nextApp)
+		Expect(job.Name).To(Equal("shop-purge"))
+		Expect(job.OwnerReferences).To(BeEmpty())
+		Expect(job.Labels).To(HaveKeyWithValue(nextAppLabel, "shop"))
+		Expect(job.Spec.ActiveDeadlineSeconds).To(HaveValue(BeEquivalentTo(purgeDeadlineSeconds)))
+		Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal("shop-sa"))
+		Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(job.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(targets).To(Equal("gs://assets/shop/"))
+
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal(purgeGCSImage))
+		Expect(env(container)).To(HaveKeyWithValue("GCS_BUCKET_KEY_PREFIX", "shop"))
+		Expect(env(container)).To(HaveKeyWithValue("CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE",
+			storageCredentialsMountPath+"/"+appsv1alpha1.StorageCredentialsGCSKey))
+	})
+
+	It("should purge the app's S3 prefix with credentials from the Secret", func() {
+		nextApp.Spec.Storage = &appsv1alpha1.StorageSpec{
+			Provider:          appsv1alpha1.StorageProviderMinio,
+			Bucket:            "assets",
+			Endpoint:          "http://minio:9000",
+			CredentialsSecret: "minio",
+		}
+		job, targets := purgeJob(nextApp)
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal(purgeS3Image))
+		Expect(env(container)).To(HaveKeyWithValue("CACHE_BUCKET_KEY_PREFIX", "shop"))
+		Expect(env(container)).To(HaveKeyWithValue("S3_ENDPOINT", "http://minio:9000"))
+		Expect(container.Env).To(ContainElement(HaveField("Name", "AWS_SECRET_ACCESS_KEY")))
+		Expect(targets).To(Equal("s3://assets/shop/"))
+	})
+
+	It("should unlink Redis keys under the app's key prefix", func() {
+		nextApp.Spec.Storage = &appsv1alpha1.StorageSpec{Provider: appsv1alpha1.StorageProviderAzure, Bucket: "assets"}
+		nextApp.Spec.Cache = &appsv1alpha1.CacheSpec{Provider: appsv1alpha1.CacheProviderRedis, URL: "redis://redis:6379"}
+		job, targets := purgeJob(nextApp)
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Name).To(Equal("purge-redis"))
+		Expect(env(container)).To(HaveKeyWithValue("REDIS_URL", "redis://redis:6379"))
+		Expect(env(container)).To(HaveKeyWithValue("REDIS_KEY_PREFIX", "store/shop"))
+		Expect(targets).To(Equal("Redis keys store/shop/*"))
+	})
+
+	It("should use an explicit Redis key prefix", func() {
+		nextApp.Spec.Cache = &appsv1alpha1.CacheSpec{
+			TagCache: &appsv1alpha1.CacheBackendSpec{
+				Provider: appsv1alpha1.CacheProviderRedis,
+				Redis:    &appsv1alpha1.RedisCacheSpec{URL: "redis://redis:6379", KeyPrefix: "shop-prod"},
+			},
+		}
+		_, targets := purgeJob(nextApp)
+		Expect(targets).To(Equal("Redis keys shop-prod/*"))
+	})
+})
+
+var _ = Describe("purgeJobToNextApp", func() {
+	It("should map a purge Job to its NextApp by label", func() {
+		job, _ := purgeJob(&appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "store"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Cache: &appsv1alpha1.CacheSpec{Provider: appsv1alpha1.CacheProviderRedis, URL: "redis://redis:6379"},
+			},
+		})
+		Expect(NextAppSelector().Matches(labels.Set(job.Labels))).To(BeTrue())
+		Expect(purgeJobToNextApp(context.Background(), job)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "store"}}))
+
+		job.Name = "migrate"
+		Expect(purgeJobToNextApp(context.Background(), job)).To(BeEmpty())
+	})
+
+	It("should leave Jobs without the NextApp label out of the cache", func() {
+		Expect(NextAppSelector().Matches(labels.Set{"app": "shop"})).To(BeFalse())
+	})
+})
+
+var _ = Describe("reconcileDelete", func() {
+	var (
+		reconciler *NextAppReconciler
+		recorder   *events.FakeRecorder
+		nextApp    *appsv1alpha1.NextApp
+	)
+
+	BeforeEach(func() {
+		nextApp = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "shop",
+				Namespace:         "store",
+				Finalizers:        []string{teardownFinalizer},
+				DeletionTimestamp: ptr.To(metav1.Now()),
+			},
+			Spec: appsv1alpha1.NextAppSpec{
+				DeletionPolicy: appsv1alpha1.DeletionPolicyDelete,
+				Cache:          &appsv1alpha1.CacheSpec{Provider: appsv1alpha1.CacheProviderRedis, URL: "redis://redis:6379"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		recorder = events.NewFakeRecorder(10)
+		reconciler = &NextAppReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(nextApp).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	})
+
+	It("should start an unowned purge Job", func() {
+		_, err := reconciler.reconcileDelete(ctx, nextApp)
+		Expect(err).NotTo(HaveOccurred())
+
+		job := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "shop-purge", Namespace: "store"}, job)).To(Succeed())
+		Expect(job.OwnerReferences).To(BeEmpty())
+		Expect(controllerutil.ContainsFinalizer(nextApp, teardownFinalizer)).To(BeTrue())
+	})
+
+	It("should run the purge under the app's ServiceAccount and delete it when done", func() {
+		sa := appServiceAccount(nextApp)
+		Expect(controllerutil.SetControllerReference(nextApp, sa, reconciler.Scheme)).To(Succeed())
+		Expect(reconciler.Create(ctx, sa)).To(Succeed())
+
+		_, err := reconciler.reconcileDelete(ctx, nextApp)
+		Expect(err).NotTo(HaveOccurred())
+		job := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "shop-purge", Namespace: "store"}, job)).To(Succeed())
+		Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal("shop-sa"))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "shop-sa", Namespace: "store"}, sa)).To(Succeed())
+		Expect(sa.OwnerReferences).To(BeEmpty())
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		Expect(reconciler.Status().Update(ctx, job)).To(Succeed())
+		_, err = reconciler.reconcileDelete(ctx, nextApp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllerutil.ContainsFinalizer(nextApp, teardownFinalizer)).To(BeFalse())
+		err = reconciler.Get(ctx, types.NamespacedName{Name: "shop-sa", Namespace: "store"}, &corev1.ServiceAccount{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should recreate a ServiceAccount garbage collection already deleted", func() {
+		_, err := reconciler.reconcileDelete(ctx, nextApp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "shop-sa", Namespace: "store"}, &corev1.ServiceAccount{})).To(Succeed())
+	})
+
+	It("should release the NextApp and delete the Job when the purge times out", func() {
+		job, _ := purgeJob(nextApp)
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type:   batchv1.JobFailed,
+			Status: corev1.ConditionTrue,
+			Reason: batchv1.JobReasonDeadlineExceeded,
+		}}
+		Expect(reconciler.Create(ctx, job)).To(Succeed())
+
+		_, err := reconciler.reconcileDelete(ctx, nextApp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal(
+			"Warning PurgeFailed Purge Job shop-purge did not finish within 600s, Redis keys store/shop/* must be cleaned up manually")))
+		Expect(controllerutil.ContainsFinalizer(nextApp, teardownFinalizer)).To(BeFalse())
+		err = reconciler.Get(ctx, types.NamespacedName{Name: "shop-purge", Namespace: "store"}, &batchv1.Job{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
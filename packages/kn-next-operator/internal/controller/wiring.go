@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podWiring collects the env vars, volumes and mounts a feature contributes
+// to the Next.js container.
+type podWiring struct {
+	env     []corev1.EnvVar
+	volumes []corev1.Volume
+	mounts  []corev1.VolumeMount
+}
+
+func (w *podWiring) addEnv(name, value string) {
+	w.env = append(w.env, corev1.EnvVar{Name: name, Value: value})
+}
+
+func (w *podWiring) addSecretEnv(name, secretName, key string) {
+	w.env = append(w.env, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	})
+}
+
+// addSecretVolume mounts a Secret read-only at mountPath.
+func (w *podWiring) addSecretVolume(volumeName, secretName, mountPath string) {
+	w.volumes = append(w.volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+		},
+	})
+	w.mounts = append(w.mounts, corev1.VolumeMount{
+		Name:      volumeName,
+		MountPath: mountPath,
+		ReadOnly:  true,
+	})
+}
+
+// merge appends other's wiring. Env vars already present are replaced, so a
+// later feature wins, matching how the TypeScript generator spreads env maps.
+func (w *podWiring) merge(other podWiring) {
+	for _, env := range other.env {
+		replaced := false
+		for i := range w.env {
+			if w.env[i].Name == env.Name {
+				w.env[i] = env
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			w.env = append(w.env, env)
+		}
+	}
+	w.volumes = append(w.volumes, other.volumes...)
+	w.mounts = append(w.mounts, other.mounts...)
+}
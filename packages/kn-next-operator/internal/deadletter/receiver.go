@@ -0,0 +1,202 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deadletter implements a small HTTP receiver used as the dead-letter
+// sink for revalidation events. It counts the events dead-lettered for each
+// NextApp in the NextApp status.
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// Receiver accepts dead-lettered CloudEvents on /<namespace>/<name> and adds
+// them to the NextApp's status.deadLetter count.
+//
+// Counts are written straight to the status with optimistic concurrency, so
+// the receiver runs on every manager replica and the Service in front of it
+// may balance across all of them. Requests that are not CloudEvents, and paths
+// that do not name an existing NextApp dead-lettering to the receiver, are
+// refused without being counted. The receiver does not authenticate callers;
+// config/network-policy limits who can reach it to the Eventing dispatchers.
+type Receiver struct {
+	// Address the HTTP server binds to, e.g. :8082
+	BindAddress string
+
+	// Base URL the receiver is reachable at from the cluster
+	BaseURL string
+
+	client   client.Client
+	reader   client.Reader
+	recorder events.EventRecorder
+}
+
+// NewReceiver returns a Receiver listening on bindAddress and reachable at
+// baseURL. It reads NextApps with reader, which should bypass the cache so a
+// replica that is not the leader does not retry against a stale copy, and
+// writes their status with c. recorder is optional.
+func NewReceiver(bindAddress, baseURL string, c client.Client, reader client.Reader, recorder events.EventRecorder) *Receiver {
+	return &Receiver{
+		BindAddress: bindAddress,
+		BaseURL:     strings.TrimSuffix(baseURL, "/"),
+		client:      c,
+		reader:      reader,
+		recorder:    recorder,
+	}
+}
+
+// SinkURL is the dead-letter sink URI for a NextApp.
+func (r *Receiver) SinkURL(namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", r.BaseURL, namespace, name)
+}
+
+// ServeHTTP counts a dead-lettered event for the NextApp named in the path.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, "expected /<namespace>/<name>", http.StatusNotFound)
+		return
+	}
+	// Dispatchers deliver dead-lettered events in binary content mode
+	if req.Header.Get("ce-id") == "" || req.Header.Get("ce-source") == "" {
+		http.Error(w, "expected a CloudEvent with ce-id and ce-source", http.StatusBadRequest)
+		return
+	}
+	key := types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	log := logf.FromContext(req.Context())
+
+	nextApp, err := r.record(req.Context(), key, time.Now())
+	if apierrors.IsNotFound(err) {
+		http.Error(w, "no such NextApp", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, errNotSink) {
+		http.Error(w, "NextApp does not dead-letter to this receiver", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		log.Error(err, "Failed to record dead-lettered event", "nextapp", key)
+		http.Error(w, "failed to record event", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info("Revalidation event dead-lettered", "nextapp", key,
+		"type", req.Header.Get("ce-type"), "id", req.Header.Get("ce-id"))
+	if r.recorder != nil {
+		r.recorder.Eventf(nextApp, nil, corev1.EventTypeWarning, "RevalidationDeadLettered", "DeadLetter",
+			"A revalidation event exhausted its retries and was dead-lettered")
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// errNotSink refuses events for a NextApp whose revalidation events are not
+// dead-lettered to the receiver.
+var errNotSink = errors.New("NextApp does not dead-letter to the receiver")
+
+// record adds one event at the given time to the NextApp's dead-letter
+// count, retrying on conflicting status writes.
+func (r *Receiver) record(ctx context.Context, key types.NamespacedName, at time.Time) (*appsv1alpha1.NextApp, error) {
+	nextApp := &appsv1alpha1.NextApp{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.reader.Get(ctx, key, nextApp); err != nil {
+			return err
+		}
+		if !UsesReceiver(nextApp) {
+			return errNotSink
+		}
+		countEvent(nextApp, at)
+		return r.client.Status().Update(ctx, nextApp)
+	})
+	return nextApp, err
+}
+
+// UsesReceiver reports whether the NextApp's revalidation events are
+// dead-lettered to the receiver: a queue with delivery settings and no
+// dead-letter sink of its own.
+func UsesReceiver(nextApp *appsv1alpha1.NextApp) bool {
+	revalidation := nextApp.Spec.Revalidation
+	return revalidation != nil && revalidation.Queue != "" &&
+		revalidation.Delivery != nil && revalidation.Delivery.DeadLetterSinkURI == ""
+}
+
+// countEvent adds an event dead-lettered at the given time to the NextApp
+// status.
+func countEvent(nextApp *appsv1alpha1.NextApp, at time.Time) {
+	if nextApp.Status.DeadLetter == nil {
+		nextApp.Status.DeadLetter = &appsv1alpha1.DeadLetterStatus{}
+	}
+	nextApp.Status.DeadLetter.Events++
+	nextApp.Status.DeadLetter.LastEventTime = &metav1.Time{Time: at}
+}
+
+// NeedLeaderElection keeps the receiver serving on every replica. It
+// implements manager.LeaderElectionRunnable.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the receiver until ctx is cancelled. It implements
+// manager.Runnable.
+func (r *Receiver) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("deadletter")
+	server := &http.Server{
+		Addr:              r.BindAddress,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(_ net.Listener) context.Context {
+			return logf.IntoContext(context.Background(), log)
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("Starting dead-letter receiver", "address", r.BindAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("Receiver", func() {
+	var (
+		receiver *Receiver
+		c        client.Client
+		recorder *events.FakeRecorder
+		key      types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(appsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		nextApp := &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "shop"},
+			Spec: appsv1alpha1.NextAppSpec{Revalidation: &appsv1alpha1.RevalidationSpec{
+				Queue:    appsv1alpha1.RevalidationQueueBroker,
+				Delivery: &appsv1alpha1.DeliverySpec{Retry: ptr.To[int32](3)},
+			}},
+		}
+		customSink := &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "blog"},
+			Spec: appsv1alpha1.NextAppSpec{Revalidation: &appsv1alpha1.RevalidationSpec{
+				Queue:    appsv1alpha1.RevalidationQueueBroker,
+				Delivery: &appsv1alpha1.DeliverySpec{DeadLetterSinkURI: "http://dlq.team-a.svc"},
+			}},
+		}
+		noQueue := &appsv1alpha1.NextApp{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "docs"}}
+		c = fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(nextApp, customSink, noQueue).WithStatusSubresource(nextApp, customSink, noQueue).Build()
+		recorder = events.NewFakeRecorder(10)
+		receiver = NewReceiver(":8082", "http://dead-letter.kn-next-operator-system.svc.cluster.local:8082/",
+			c, c, recorder)
+		key = types.NamespacedName{Namespace: "team-a", Name: "shop"}
+	})
+
+	post := func(path string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("ce-id", "1")
+		req.Header.Set("ce-source", "/apis/v1/namespaces/team-a/kafkasources/shop-revalidation-source")
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	deadLetter := func() *appsv1alpha1.DeadLetterStatus {
+		nextApp := &appsv1alpha1.NextApp{}
+		Expect(c.Get(context.Background(), key, nextApp)).To(Succeed())
+		return nextApp.Status.DeadLetter
+	}
+
+	It("should build a sink URL per NextApp", func() {
+		Expect(receiver.SinkURL("team-a", "shop")).To(
+			Equal("http://dead-letter.kn-next-operator-system.svc.cluster.local:8082/team-a/shop"))
+	})
+
+	It("should run on every replica", func() {
+		Expect(receiver.NeedLeaderElection()).To(BeFalse())
+	})
+
+	It("should count events in the NextApp status", func() {
+		Expect(post("/team-a/shop")).To(Equal(http.StatusAccepted))
+		Expect(post("/team-a/shop")).To(Equal(http.StatusAccepted))
+
+		Expect(deadLetter().Events).To(Equal(int64(2)))
+		Expect(deadLetter().LastEventTime).NotTo(BeNil())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning RevalidationDeadLettered")))
+	})
+
+	It("should refuse events for a NextApp that does not exist", func() {
+		Expect(post("/team-a/other")).To(Equal(http.StatusNotFound))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should refuse events for a NextApp that does not dead-letter to the receiver", func() {
+		Expect(post("/team-a/blog")).To(Equal(http.StatusForbidden))
+		Expect(post("/team-b/docs")).To(Equal(http.StatusForbidden))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should refuse requests that are not CloudEvents", func() {
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/team-a/shop", nil))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(deadLetter()).To(BeNil())
+	})
+
+	It("should reject malformed requests", func() {
+		Expect(post("/team-a")).To(Equal(http.StatusNotFound))
+		Expect(post("/team-a/shop/extra")).To(Equal(http.StatusNotFound))
+
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team-a/shop", nil))
+		Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(deadLetter()).To(BeNil())
+	})
+})
@@ -0,0 +1,166 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var nextapplog = logf.Log.WithName("nextapp-resource")
+
+// SetupNextAppWebhookWithManager registers the webhook for NextApp in the manager.
+func SetupNextAppWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &appsv1alpha1.NextApp{}).
+		WithValidator(&NextAppCustomValidator{}).
+		WithDefaulter(&NextAppCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-apps-kn-next-dev-v1alpha1-nextapp,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps.kn-next.dev,resources=nextapps,verbs=create;update,versions=v1alpha1,name=mnextapp-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// NextAppCustomDefaulter writes the operator defaults into the stored spec, so
+// kubectl, GitOps diffs and the reconciler all see the effective configuration.
+type NextAppCustomDefaulter struct{}
+
+// Default implements admission.Defaulter so a webhook will be registered for the type NextApp.
+func (d *NextAppCustomDefaulter) Default(_ context.Context, nextApp *appsv1alpha1.NextApp) error {
+	nextapplog.Info("Defaulting for NextApp", "name", nextApp.GetName())
+
+	defaultNextAppSpec(&nextApp.Spec)
+	return nil
+}
+
+// defaultNextAppSpec fills unset scaling and bytecode cache fields. Zero is
+// treated as unset, matching how the reconciler reads these fields.
+func defaultNextAppSpec(spec *appsv1alpha1.NextAppSpec) {
+	if spec.Scaling == nil {
+		spec.Scaling = &appsv1alpha1.ScalingSpec{MinScale: appsv1alpha1.DefaultMinScale}
+	}
+	if spec.Scaling.MaxScale == 0 {
+		// Never default below an explicit minScale
+		spec.Scaling.MaxScale = max(appsv1alpha1.DefaultMaxScale, spec.Scaling.MinScale)
+	}
+	if spec.Scaling.ContainerConcurrency == 0 {
+		spec.Scaling.ContainerConcurrency = appsv1alpha1.DefaultContainerConcurrency
+	}
+
+	if cache := spec.Cache; cache != nil && cache.EnableBytecodeCache && cache.BytecodeCacheSize == "" {
+		cache.BytecodeCacheSize = appsv1alpha1.DefaultBytecodeCacheSize
+	}
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-apps-kn-next-dev-v1alpha1-nextapp,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.kn-next.dev,resources=nextapps,verbs=create;update,versions=v1alpha1,name=vnextapp-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// NextAppCustomValidator rejects NextApps the reconciler cannot act on.
+//
+// The CRD carries CEL rules for the same checks; the webhook repeats them so
+// clusters without CEL support, and objects stored before the rules existed,
+// are still rejected on update.
+type NextAppCustomValidator struct{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type NextApp.
+func (v *NextAppCustomValidator) ValidateCreate(_ context.Context, nextApp *appsv1alpha1.NextApp) (admission.Warnings, error) {
+	nextapplog.Info("Validation for NextApp upon creation", "name", nextApp.GetName())
+
+	return nil, validateNextApp(nextApp)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type NextApp.
+func (v *NextAppCustomValidator) ValidateUpdate(_ context.Context, _, nextApp *appsv1alpha1.NextApp) (admission.Warnings, error) {
+	nextapplog.Info("Validation for NextApp upon update", "name", nextApp.GetName())
+
+	return nil, validateNextApp(nextApp)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type NextApp.
+func (v *NextAppCustomValidator) ValidateDelete(_ context.Context, _ *appsv1alpha1.NextApp) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateNextApp returns an Invalid error listing every offending field, or
+// nil when the spec is acceptable.
+func validateNextApp(nextApp *appsv1alpha1.NextApp) error {
+	allErrs := validateNextAppSpec(&nextApp.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		appsv1alpha1.GroupVersion.WithKind("NextApp").GroupKind(),
+		nextApp.Name, allErrs)
+}
+
+func validateNextAppSpec(spec *appsv1alpha1.NextAppSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// maxScale 0 means unset when the defaulting webhook is disabled
+	if scaling := spec.Scaling; scaling != nil && scaling.MaxScale > 0 && scaling.MinScale > scaling.MaxScale {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scaling", "minScale"),
+			scaling.MinScale, "must not exceed maxScale"))
+	}
+
+	if cache := spec.Cache; cache != nil && cache.BytecodeCacheSize != "" {
+		if _, err := resource.ParseQuantity(cache.BytecodeCacheSize); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cache", "bytecodeCacheSize"),
+				cache.BytecodeCacheSize, err.Error()))
+		}
+	}
+
+	if revalidation := spec.Revalidation; revalidation != nil &&
+		revalidation.Queue == appsv1alpha1.RevalidationQueueKafka && revalidation.KafkaBrokerUrl == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("revalidation", "kafkaBrokerUrl"),
+			"required for the kafka queue"))
+	}
+
+	if spec.DeletionPolicy == appsv1alpha1.DeletionPolicyDelete &&
+		spec.Storage != nil && spec.Storage.Provider == appsv1alpha1.StorageProviderAzure {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("deletionPolicy"), spec.DeletionPolicy,
+			"not supported with azure storage, which has no per-app prefix"))
+	}
+
+	if probes := spec.Probes; probes != nil {
+		// Knative probes the user container on its serving port
+		for _, probe := range []struct {
+			name string
+			spec *appsv1alpha1.ProbeSpec
+		}{{"readiness", probes.Readiness}, {"liveness", probes.Liveness}, {"startup", probes.Startup}} {
+			if probe.spec != nil && probe.spec.Port != 0 && probe.spec.Port != appsv1alpha1.ContainerPort {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("probes", probe.name, "port"), probe.spec.Port,
+					fmt.Sprintf("must be the app container port %d", appsv1alpha1.ContainerPort)))
+			}
+		}
+	}
+
+	if preview := spec.Preview; preview != nil && preview.Enabled && preview.PRID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("preview", "prId"),
+			"required when preview is enabled"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+)
+
+var _ = Describe("NextApp Webhook", func() {
+	var (
+		obj       *appsv1alpha1.NextApp
+		oldObj    *appsv1alpha1.NextApp
+		validator NextAppCustomValidator
+		defaulter NextAppCustomDefaulter
+	)
+
+	// causes returns the field paths an Invalid error rejects.
+	causes := func(err error) []string {
+		status, ok := err.(apierrors.APIStatus)
+		Expect(ok).To(BeTrue())
+		var fields []string
+		for _, cause := range status.Status().Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		return fields
+	}
+
+	BeforeEach(func() {
+		obj = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+			Spec: appsv1alpha1.NextAppSpec{
+				Image:   "registry.example.com/shop:1.0.0",
+				Scaling: &appsv1alpha1.ScalingSpec{MinScale: 1, MaxScale: 5},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = NextAppCustomValidator{}
+		defaulter = NextAppCustomDefaulter{}
+	})
+
+	Context("When creating NextApp under Defaulting Webhook", func() {
+		It("Should materialize the scaling defaults", func() {
+			obj.Spec.Scaling = nil
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(*obj.Spec.Scaling).To(Equal(appsv1alpha1.ScalingSpec{
+				MinScale:             0,
+				MaxScale:             10,
+				ContainerConcurrency: 100,
+			}))
+		})
+
+		It("Should keep explicit scaling values", func() {
+			obj.Spec.Scaling.ContainerConcurrency = 20
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Scaling.MinScale).To(Equal(int32(1)))
+			Expect(obj.Spec.Scaling.MaxScale).To(Equal(int32(5)))
+			Expect(obj.Spec.Scaling.ContainerConcurrency).To(Equal(int32(20)))
+		})
+
+		It("Should not default maxScale below minScale", func() {
+			obj.Spec.Scaling = &appsv1alpha1.ScalingSpec{MinScale: 12}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Scaling.MaxScale).To(Equal(int32(12)))
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should size an enabled bytecode cache", func() {
+			obj.Spec.Cache = &appsv1alpha1.CacheSpec{EnableBytecodeCache: true}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Cache.BytecodeCacheSize).To(Equal("512Mi"))
+		})
+
+		It("Should leave a disabled bytecode cache unsized", func() {
+			obj.Spec.Cache = &appsv1alpha1.CacheSpec{Provider: "redis"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Cache.BytecodeCacheSize).To(BeEmpty())
+		})
+	})
+
+	Context("When creating or updating NextApp under Validating Webhook", func() {
+		It("Should admit a valid NextApp", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny minScale above maxScale", func() {
+			obj.Spec.Scaling.MinScale = 6
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(causes(err)).To(ConsistOf("spec.scaling.minScale"))
+		})
+
+		It("Should admit minScale without an upper bound", func() {
+			obj.Spec.Scaling.MaxScale = 0
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny the kafka queue without a broker URL", func() {
+			obj.Spec.Revalidation = &appsv1alpha1.RevalidationSpec{Queue: appsv1alpha1.RevalidationQueueKafka}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(causes(err)).To(ConsistOf("spec.revalidation.kafkaBrokerUrl"))
+		})
+
+		It("Should deny a malformed bytecode cache size", func() {
+			obj.Spec.Cache = &appsv1alpha1.CacheSpec{EnableBytecodeCache: true, BytecodeCacheSize: "512MB"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causes(err)).To(ConsistOf("spec.cache.bytecodeCacheSize"))
+		})
+
+		It("Should deny a preview without a PR ID", func() {
+			obj.Spec.Preview = &appsv1alpha1.PreviewSpec{Enabled: true, Branch: "feature"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causes(err)).To(ConsistOf("spec.preview.prId"))
+		})
+
+		It("Should deny the Delete policy with azure storage", func() {
+			obj.Spec.Storage = &appsv1alpha1.StorageSpec{Provider: appsv1alpha1.StorageProviderAzure, Bucket: "assets"}
+			obj.Spec.DeletionPolicy = appsv1alpha1.DeletionPolicyDelete
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causes(err)).To(ConsistOf("spec.deletionPolicy"))
+		})
+
+		It("Should deny a probe port other than the container port", func() {
+			obj.Spec.Probes = &appsv1alpha1.ProbesSpec{
+				Readiness: &appsv1alpha1.ProbeSpec{Port: 3000},
+				Startup:   &appsv1alpha1.ProbeSpec{Port: 8080},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causes(err)).To(ConsistOf("spec.probes.startup.port"))
+		})
+
+		It("Should report every invalid field at once", func() {
+			obj.Spec.Scaling.MinScale = 6
+			obj.Spec.Preview = &appsv1alpha1.PreviewSpec{Enabled: true}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causes(err)).To(ConsistOf("spec.scaling.minScale", "spec.preview.prId"))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	appsv1beta1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1beta1"
+)
+
+// SetupNextAppWebhookWithManager registers the conversion webhook for NextApp
+// in the manager.
+//
+// v1beta1 needs no admission webhooks of its own: the v1alpha1 webhooks use
+// the default Equivalent match policy, so the API server converts v1beta1
+// requests to v1alpha1 before defaulting and validating them.
+func SetupNextAppWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &appsv1beta1.NextApp{}).
+		Complete()
+}
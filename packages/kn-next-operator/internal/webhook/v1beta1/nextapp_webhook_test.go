@@ -0,0 +1,206 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appsv1alpha1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1alpha1"
+	appsv1beta1 "github.com/AhmedElBanna80/Knative-open-nextjs/packages/kn-next-operator/api/v1beta1"
+)
+
+var _ = Describe("NextApp Webhook", func() {
+	var hub *appsv1alpha1.NextApp
+
+	// roundTrip converts a v1alpha1 NextApp to v1beta1 and back.
+	roundTrip := func(in *appsv1alpha1.NextApp) (*appsv1beta1.NextApp, *appsv1alpha1.NextApp) {
+		spoke := &appsv1beta1.NextApp{}
+		Expect(spoke.ConvertFrom(in.DeepCopy())).To(Succeed())
+		out := &appsv1alpha1.NextApp{}
+		Expect(spoke.DeepCopy().ConvertTo(out)).To(Succeed())
+		return spoke, out
+	}
+
+	BeforeEach(func() {
+		hub = &appsv1alpha1.NextApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "shop",
+				Namespace:   "default",
+				Annotations: map[string]string{"team": "storefront"},
+			},
+			Spec: appsv1alpha1.NextAppSpec{
+				Image: "registry.example.com/shop:1.0.0",
+				Scaling: &appsv1alpha1.ScalingSpec{
+					MinScale:             1,
+					MaxScale:             5,
+					ContainerConcurrency: 50,
+					MemoryLimit:          ptr.To(resource.MustParse("2Gi")),
+				},
+				Storage: &appsv1alpha1.StorageSpec{
+					Provider:          appsv1alpha1.StorageProviderS3,
+					Bucket:            "shop-assets",
+					Region:            "eu-west-1",
+					CredentialsSecret: "shop-s3",
+				},
+				Cache: &appsv1alpha1.CacheSpec{
+					IncrementalCache: &appsv1alpha1.CacheBackendSpec{Provider: appsv1alpha1.CacheProviderS3},
+					TagCache: &appsv1alpha1.CacheBackendSpec{
+						Provider: appsv1alpha1.CacheProviderDynamoDB,
+						DynamoDB: &appsv1alpha1.DynamoDBCacheSpec{TableName: "tags", Region: "eu-west-1"},
+					},
+					EnableBytecodeCache: true,
+					BytecodeCacheSize:   "1Gi",
+				},
+				Revalidation: &appsv1alpha1.RevalidationSpec{
+					Queue:          appsv1alpha1.RevalidationQueueKafka,
+					KafkaBrokerUrl: "kafka-bootstrap.kafka:9092",
+					Broker:         "default",
+					Topic:          &appsv1alpha1.KafkaTopicSpec{Name: "shop-isr", Partitions: 3, ReplicationFactor: 1},
+					Auth: &appsv1alpha1.KafkaAuthSpec{
+						SASL: &appsv1alpha1.KafkaSASLSpec{CredentialsSecret: "kafka-user"},
+						TLS:  &appsv1alpha1.KafkaTLSSpec{Enabled: true},
+					},
+					Delivery: &appsv1alpha1.DeliverySpec{Retry: ptr.To(int32(3))},
+				},
+				Secrets: &appsv1alpha1.SecretsSpec{
+					EnvFrom: []string{"shop-env"},
+					EnvMap:  map[string]appsv1alpha1.SecretKeyRef{"STRIPE_KEY": {Name: "stripe", Key: "key"}},
+				},
+				Preview: &appsv1alpha1.PreviewSpec{Enabled: true, Branch: "feature", PRID: "42"},
+				Probes: &appsv1alpha1.ProbesSpec{
+					Readiness: &appsv1alpha1.ProbeSpec{Path: "/ready", PeriodSeconds: ptr.To[int32](5)},
+				},
+				Observability: &appsv1alpha1.ObservabilitySpec{
+					Enabled:    true,
+					Prometheus: &appsv1alpha1.PrometheusSpec{ScrapeInterval: "30s"},
+				},
+				Rollout: &appsv1alpha1.RolloutSpec{Steps: []appsv1alpha1.RolloutStep{
+					{Percent: 10, Pause: metav1.Duration{Duration: 5 * time.Minute}},
+				}},
+				Rollback: &appsv1alpha1.RollbackSpec{Enabled: true},
+				Domains: []appsv1alpha1.DomainSpec{{
+					Host:   "shop.example.de",
+					Locale: "de",
+					TLS:    &appsv1alpha1.DomainTLSSpec{IssuerRef: &appsv1alpha1.IssuerRef{Name: "letsencrypt"}},
+				}},
+				DeletionPolicy: appsv1alpha1.DeletionPolicyDelete,
+				DriftPolicy:    appsv1alpha1.DriftPolicyReport,
+			},
+			Status: appsv1alpha1.NextAppStatus{
+				URL:                 "https://shop.default.example.com",
+				ObservedGeneration:  2,
+				LatestReadyRevision: "shop-00002",
+				Traffic: []appsv1alpha1.TrafficStatus{
+					{RevisionName: "shop-00001", Percent: 90},
+					{RevisionName: "shop-00002", Tag: "canary", Percent: 10, URL: "https://canary-shop.default.example.com"},
+				},
+				Domains: []appsv1alpha1.DomainStatus{{Host: "shop.example.de", Ready: metav1.ConditionTrue}},
+				Conditions: []metav1.Condition{{
+					Type:   "Ready",
+					Status: metav1.ConditionTrue,
+					Reason: "Reconciled",
+				}},
+			},
+		}
+	})
+
+	Context("When converting NextApp under Conversion Webhook", func() {
+		It("Should round-trip a v1alpha1 NextApp losslessly", func() {
+			spoke, out := roundTrip(hub)
+			Expect(out).To(Equal(hub))
+			Expect(spoke.Annotations).NotTo(HaveKey(appsv1beta1.V1alpha1SpecAnnotation))
+		})
+
+		It("Should restructure providers and Secret references", func() {
+			spoke, _ := roundTrip(hub)
+			Expect(spoke.Spec.Storage.S3).To(Equal(&appsv1beta1.S3StorageSpec{
+				Bucket:               "shop-assets",
+				Region:               "eu-west-1",
+				CredentialsSecretRef: &appsv1beta1.SecretReference{Name: "shop-s3"},
+			}))
+			Expect(spoke.Spec.Cache.Incremental.S3).NotTo(BeNil())
+			Expect(spoke.Spec.Cache.Tag.DynamoDB.TableName).To(Equal("tags"))
+			Expect(spoke.Spec.Cache.Bytecode.Size.String()).To(Equal("1Gi"))
+			Expect(spoke.Spec.Revalidation.Kafka.BootstrapServers).To(Equal("kafka-bootstrap.kafka:9092"))
+			Expect(spoke.Spec.Revalidation.Kafka.Auth.SASL.CredentialsSecretRef.Name).To(Equal("kafka-user"))
+			Expect(spoke.Spec.Secrets.EnvFrom).To(ConsistOf(appsv1beta1.SecretReference{Name: "shop-env"}))
+			Expect(spoke.Spec.Preview).To(Equal(&appsv1beta1.PreviewSpec{PullRequest: "42", Branch: "feature"}))
+		})
+
+		It("Should restore v1alpha1 fields v1beta1 cannot represent", func() {
+			hub.Spec.Cache = &appsv1alpha1.CacheSpec{Provider: appsv1alpha1.CacheProviderRedis, URL: "redis://redis:6379"}
+			hub.Spec.Preview = &appsv1alpha1.PreviewSpec{Enabled: false, Branch: "feature"}
+			hub.Spec.Revalidation.Broker = "isr"
+
+			spoke, out := roundTrip(hub)
+			Expect(spoke.Spec.Cache.Incremental.Redis.URL).To(Equal("redis://redis:6379"))
+			Expect(spoke.Spec.Cache.Tag.Redis.URL).To(Equal("redis://redis:6379"))
+			Expect(spoke.Spec.Preview).To(BeNil())
+			Expect(spoke.Annotations).To(HaveKey(appsv1beta1.V1alpha1SpecAnnotation))
+			Expect(out).To(Equal(hub))
+		})
+
+		It("Should drop the stashed spec once the v1beta1 spec is edited", func() {
+			hub.Spec.Cache = &appsv1alpha1.CacheSpec{Provider: appsv1alpha1.CacheProviderRedis, URL: "redis://redis:6379"}
+			spoke := &appsv1beta1.NextApp{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			spoke.Spec.Cache.Tag = nil
+			out := &appsv1alpha1.NextApp{}
+			Expect(spoke.ConvertTo(out)).To(Succeed())
+			Expect(out.Spec.Cache.Provider).To(BeEmpty())
+			Expect(out.Spec.Cache.IncrementalCache.Redis.URL).To(Equal("redis://redis:6379"))
+			Expect(out.Spec.Cache.TagCache).To(BeNil())
+			Expect(out.Annotations).To(Equal(map[string]string{"team": "storefront"}))
+		})
+
+		It("Should round-trip a v1beta1 NextApp losslessly", func() {
+			spoke := &appsv1beta1.NextApp{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+				Spec: appsv1beta1.NextAppSpec{
+					Image: "registry.example.com/shop:1.0.0",
+					Storage: &appsv1beta1.StorageSpec{
+						GCS: &appsv1beta1.GCSStorageSpec{Bucket: "shop-assets"},
+					},
+					Cache: &appsv1beta1.CacheSpec{
+						Incremental: &appsv1beta1.CacheBackendSpec{GCS: &appsv1beta1.BucketCacheSpec{}},
+						Tag: &appsv1beta1.CacheBackendSpec{Redis: &appsv1beta1.RedisCacheSpec{
+							URLSecretRef: &appsv1beta1.SecretKeyRef{Name: "redis"},
+						}},
+						Bytecode: &appsv1beta1.BytecodeCacheSpec{},
+					},
+					Revalidation: &appsv1beta1.RevalidationSpec{
+						Broker: &appsv1beta1.BrokerQueueSpec{Name: "default"},
+					},
+				},
+			}
+			hub := &appsv1alpha1.NextApp{}
+			Expect(spoke.DeepCopy().ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Revalidation.Queue).To(Equal(appsv1alpha1.RevalidationQueueBroker))
+
+			out := &appsv1beta1.NextApp{}
+			Expect(out.ConvertFrom(hub)).To(Succeed())
+			Expect(out).To(Equal(spoke))
+		})
+	})
+})
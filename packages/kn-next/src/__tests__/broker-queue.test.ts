@@ -0,0 +1,105 @@
+import type { QueueMessage } from "@opennextjs/aws/types/overrides";
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+
+const mockFetch = vi.fn(
+    (_url: string, _init: RequestInit): Promise<Response> =>
+        Promise.resolve(new Response(null, { status: 202 })),
+);
+
+const message: QueueMessage = {
+    MessageBody: {
+        host: "shop.example.com",
+        url: "/blog/hello",
+        eTag: "abc",
+        lastModified: 1700000000000,
+    },
+    MessageDeduplicationId: "dedup-1",
+    MessageGroupId: "group-1",
+};
+
+describe("Broker Queue Adapter", () => {
+    let originalEnv: NodeJS.ProcessEnv;
+
+    // Imports the adapter afresh, so it reads the current env
+    const importQueue = async () => {
+        const { default: queue } = await import("../adapters/broker-queue");
+        return queue;
+    };
+
+    beforeEach(() => {
+        originalEnv = process.env;
+        process.env = {
+            ...originalEnv,
+            REVALIDATION_BROKER_URL:
+                "http://broker-ingress.knative-eventing.svc.cluster.local/store/default",
+            REVALIDATION_EVENT_SOURCE: "/kn-next/store/shop",
+        };
+        mockFetch.mockClear();
+        vi.stubGlobal("fetch", mockFetch);
+        vi.resetModules();
+        vi.spyOn(console, "error").mockImplementation(() => {});
+    });
+
+    afterEach(() => {
+        process.env = originalEnv;
+        vi.unstubAllGlobals();
+        vi.restoreAllMocks();
+    });
+
+    it("should post the revalidation as a binary-mode CloudEvent", async () => {
+        const queue = await importQueue();
+        await queue.send(message);
+
+        expect(mockFetch).toHaveBeenCalledTimes(1);
+        const [url, init] = mockFetch.mock.calls[0];
+        expect(url).toBe(
+            "http://broker-ingress.knative-eventing.svc.cluster.local/store/default",
+        );
+        expect(init.method).toBe("POST");
+        expect(init.headers).toMatchObject({
+            "ce-specversion": "1.0",
+            "ce-type": "dev.kn-next.revalidation",
+            "ce-source": "/kn-next/store/shop",
+            "ce-id": "dedup-1",
+            "content-type": "application/json",
+        });
+        expect(JSON.parse(init.body as string)).toEqual({
+            host: "shop.example.com",
+            url: "/blog/hello",
+            eTag: "abc",
+            lastModified: 1700000000000,
+        });
+    });
+
+    it("should default the event source", async () => {
+        process.env.REVALIDATION_EVENT_SOURCE = undefined;
+        const queue = await importQueue();
+        await queue.send(message);
+
+        const [, init] = mockFetch.mock.calls[0];
+        expect(init.headers).toMatchObject({ "ce-source": "/kn-next/isr" });
+    });
+
+    it("should throw when the Broker rejects the event", async () => {
+        mockFetch.mockResolvedValueOnce(
+            new Response(null, {
+                status: 503,
+                statusText: "Service Unavailable",
+            }),
+        );
+        const queue = await importQueue();
+
+        await expect(queue.send(message)).rejects.toThrow(
+            "[Broker Queue] Broker rejected event: 503 Service Unavailable",
+        );
+    });
+
+    it("should throw when the Broker URL is not set", async () => {
+        process.env.REVALIDATION_BROKER_URL = undefined;
+        const queue = await importQueue();
+
+        await expect(queue.send(message)).rejects.toThrow(
+            "[Broker Queue] REVALIDATION_BROKER_URL is not set",
+        );
+    });
+});
@@ -0,0 +1,169 @@
+import type { QueueMessage } from "@opennextjs/aws/types/overrides";
+import type { KafkaConfig } from "kafkajs";
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+
+const kafkaConfigs: KafkaConfig[] = [];
+const mockSend = vi.fn((): Promise<unknown> => Promise.resolve([]));
+const mockConnect = vi.fn(() => Promise.resolve(undefined));
+
+// Mock kafkajs before importing adapter
+vi.mock("kafkajs", () => {
+    return {
+        CompressionTypes: { GZIP: 1 },
+        Kafka: class MockKafka {
+            constructor(config: KafkaConfig) {
+                kafkaConfigs.push(config);
+            }
+            producer() {
+                return {
+                    connect: mockConnect,
+                    send: mockSend,
+                    disconnect: vi.fn(),
+                };
+            }
+        },
+    };
+});
+
+const message: QueueMessage = {
+    MessageBody: {
+        host: "shop.example.com",
+        url: "/blog/hello",
+        eTag: "abc",
+        lastModified: 1700000000000,
+    },
+    MessageDeduplicationId: "dedup-1",
+    MessageGroupId: "group-1",
+};
+
+describe("Kafka Queue Adapter", () => {
+    let originalEnv: NodeJS.ProcessEnv;
+
+    // Imports the adapter afresh, so it reads the current env
+    const importQueue = async () => {
+        const { default: queue } = await import("../adapters/kafka-queue");
+        return queue;
+    };
+    const kafkaConfig = () => kafkaConfigs[kafkaConfigs.length - 1];
+
+    beforeEach(() => {
+        originalEnv = process.env;
+        process.env = Object.fromEntries(
+            Object.entries(originalEnv).filter(
+                ([key]) => !key.startsWith("KAFKA_"),
+            ),
+        );
+        kafkaConfigs.length = 0;
+        mockSend.mockClear();
+        mockConnect.mockClear();
+        vi.resetModules();
+    });
+
+    afterEach(() => {
+        process.env = originalEnv;
+    });
+
+    describe("SASL", () => {
+        it.each([
+            ["PLAIN", "plain"],
+            ["SCRAM-SHA-256", "scram-sha-256"],
+            ["SCRAM-SHA-512", "scram-sha-512"],
+        ])(
+            "should map the Knative %s saslType to %s",
+            async (saslType, mechanism) => {
+                process.env.KAFKA_SASL_MECHANISM = saslType;
+                process.env.KAFKA_SASL_USERNAME = "shop";
+                process.env.KAFKA_SASL_PASSWORD = "secret";
+
+                await importQueue();
+
+                expect(kafkaConfig().sasl).toEqual({
+                    mechanism,
+                    username: "shop",
+                    password: "secret",
+                });
+            },
+        );
+
+        it("should connect without SASL when credentials are missing", async () => {
+            process.env.KAFKA_SASL_MECHANISM = "PLAIN";
+
+            await importQueue();
+
+            expect(kafkaConfig().sasl).toBeUndefined();
+        });
+
+        it("should reject an unsupported SASL mechanism", async () => {
+            process.env.KAFKA_SASL_MECHANISM = "OAUTHBEARER";
+            process.env.KAFKA_SASL_USERNAME = "shop";
+            process.env.KAFKA_SASL_PASSWORD = "secret";
+
+            await expect(importQueue()).rejects.toThrow(
+                "[Kafka Queue] Unsupported SASL mechanism: OAUTHBEARER",
+            );
+        });
+    });
+
+    describe("TLS", () => {
+        it("should connect in plaintext unless KAFKA_TLS is true", async () => {
+            await importQueue();
+
+            expect(kafkaConfig().ssl).toBeUndefined();
+        });
+
+        it("should trust the system CAs without a CA or client certificate", async () => {
+            process.env.KAFKA_TLS = "true";
+
+            await importQueue();
+
+            expect(kafkaConfig().ssl).toBe(true);
+        });
+
+        it("should pass the CA and client certificate", async () => {
+            process.env.KAFKA_TLS = "true";
+            process.env.KAFKA_TLS_CA = "ca-pem";
+            process.env.KAFKA_TLS_CERT = "cert-pem";
+            process.env.KAFKA_TLS_KEY = "key-pem";
+
+            await importQueue();
+
+            expect(kafkaConfig().ssl).toEqual({
+                ca: ["ca-pem"],
+                cert: "cert-pem",
+                key: "key-pem",
+            });
+        });
+    });
+
+    it("should publish the revalidation as a CloudEvent", async () => {
+        process.env.KAFKA_REVALIDATION_TOPIC = "shop-revalidation";
+
+        const queue = await importQueue();
+        await queue.send(message);
+
+        expect(mockSend).toHaveBeenCalledTimes(1);
+        const [record] = mockSend.mock.calls[0] as unknown as [
+            {
+                topic: string;
+                messages: Array<{
+                    key: string;
+                    value: string;
+                    headers: Record<string, string>;
+                }>;
+            },
+        ];
+        expect(record.topic).toBe("shop-revalidation");
+        const [sent] = record.messages;
+        expect(sent.key).toBe("group-1");
+        expect(sent.headers).toMatchObject({
+            "ce-specversion": "1.0",
+            "ce-type": "dev.kn-next.revalidation",
+            "ce-source": "/kn-next/isr",
+            "ce-id": "dedup-1",
+        });
+        expect(JSON.parse(sent.value).data).toMatchObject({
+            host: "shop.example.com",
+            url: "/blog/hello",
+        });
+    });
+});
@@ -0,0 +1,43 @@
+import { describe, expect, it } from "vitest";
+import { originalHost, parseDomains } from "../adapters/node-server";
+
+describe("Node Server", () => {
+    describe("parseDomains", () => {
+        it("should parse the comma-separated KN_DOMAINS hosts", () => {
+            expect(parseDomains(" Shop.example.com,shop.example.de ,")).toEqual(
+                new Set(["shop.example.com", "shop.example.de"]),
+            );
+        });
+
+        it("should return no domains when KN_DOMAINS is unset", () => {
+            expect(parseDomains(undefined).size).toBe(0);
+        });
+    });
+
+    describe("originalHost", () => {
+        const domains = parseDomains("shop.example.com,shop.example.de");
+
+        it("should restore a K-Original-Host naming one of the app's domains", () => {
+            expect(originalHost("shop.example.de", domains)).toBe(
+                "shop.example.de",
+            );
+            expect(originalHost("Shop.Example.com:8443", domains)).toBe(
+                "shop.example.com:8443",
+            );
+        });
+
+        it("should ignore a K-Original-Host for any other host", () => {
+            expect(originalHost("evil.example.net", domains)).toBeUndefined();
+            expect(
+                originalHost("shop.example.com", parseDomains(undefined)),
+            ).toBeUndefined();
+        });
+
+        it("should ignore a missing or repeated header", () => {
+            expect(originalHost(undefined, domains)).toBeUndefined();
+            expect(
+                originalHost(["shop.example.com", "shop.example.de"], domains),
+            ).toBeUndefined();
+        });
+    });
+});
@@ -87,6 +87,28 @@ describe("OpenNext Config Generator", () => {
         expect(content).toContain("kafka-queue");
     });
 
+    it("should use the Broker queue for the broker provider", () => {
+        const config: KnativeNextConfig = {
+            name: "test-app",
+            storage: {
+                provider: "gcs",
+                bucket: "test-bucket",
+                publicUrl: "https://storage.googleapis.com/test-bucket",
+            },
+            registry: "gcr.io/test-project",
+            queue: { provider: "broker" },
+        };
+
+        const outputPath = generateOpenNextConfig({
+            config,
+            outputDir: tempDir,
+        });
+
+        const content = readFileSync(outputPath, "utf-8");
+        expect(content).toContain("broker-queue");
+        expect(content).toContain("Queue provider: broker");
+    });
+
     describe("getRequiredEnvVars", () => {
         it("should return GCS env vars for GCS provider", () => {
             const config: KnativeNextConfig = {
@@ -140,6 +162,7 @@ describe("OpenNext Config Generator", () => {
             const envVars = getRequiredEnvVars(config);
 
             expect(envVars.CACHE_BUCKET_NAME).toBe("my-bucket");
+            expect(envVars.CACHE_BUCKET_KEY_PREFIX).toBe("test-app");
             expect(envVars.S3_ENDPOINT).toBe("http://minio:9000");
         });
     });
@@ -0,0 +1,52 @@
+import type { Queue, QueueMessage } from "@opennextjs/aws/types/overrides";
+
+const { REVALIDATION_BROKER_URL, REVALIDATION_EVENT_SOURCE } = process.env;
+
+/**
+ * Knative Broker Queue adapter for OpenNext ISR revalidation.
+ *
+ * Posts CloudEvents (binary content mode) to the Broker ingress URL. The
+ * kn-next operator creates a Trigger that filters on the event type and the
+ * per-app source, so apps sharing a Broker only receive their own events.
+ *
+ * Works with any Knative Eventing broker class (in-memory, RabbitMQ, Kafka).
+ */
+const queue: Queue = {
+    name: "broker",
+
+    async send(message: QueueMessage): Promise<void> {
+        if (!REVALIDATION_BROKER_URL) {
+            throw new Error(
+                "[Broker Queue] REVALIDATION_BROKER_URL is not set",
+            );
+        }
+
+        const response = await fetch(REVALIDATION_BROKER_URL, {
+            method: "POST",
+            headers: {
+                "ce-specversion": "1.0",
+                "ce-type": "dev.kn-next.revalidation",
+                "ce-source": REVALIDATION_EVENT_SOURCE ?? "/kn-next/isr",
+                "ce-id": message.MessageDeduplicationId,
+                "ce-time": new Date().toISOString(),
+                "content-type": "application/json",
+            },
+            body: JSON.stringify({
+                host: message.MessageBody.host,
+                url: message.MessageBody.url,
+                lastModified: message.MessageBody.lastModified,
+                eTag: message.MessageBody.eTag,
+            }),
+        });
+
+        if (!response.ok) {
+            const error = new Error(
+                `[Broker Queue] Broker rejected event: ${response.status} ${response.statusText}`,
+            );
+            console.error(error.message);
+            throw error;
+        }
+    },
+};
+
+export default queue;
@@ -1,13 +1,72 @@
 import type { Queue, QueueMessage } from "@opennextjs/aws/types/overrides";
-import { CompressionTypes, Kafka, type Producer } from "kafkajs";
+import {
+    CompressionTypes,
+    Kafka,
+    type KafkaConfig,
+    type Producer,
+    type SASLOptions,
+} from "kafkajs";
 
-const { KAFKA_BROKER_URL, KAFKA_REVALIDATION_TOPIC, KAFKA_CLIENT_ID } =
-    process.env;
+const {
+    KAFKA_BROKER_URL,
+    KAFKA_REVALIDATION_TOPIC,
+    KAFKA_CLIENT_ID,
+    KAFKA_SASL_MECHANISM,
+    KAFKA_SASL_USERNAME,
+    KAFKA_SASL_PASSWORD,
+    KAFKA_TLS,
+    KAFKA_TLS_CA,
+    KAFKA_TLS_CERT,
+    KAFKA_TLS_KEY,
+} = process.env;
+
+// TLS to the brokers; CA and client certificate are PEM strings from Secrets
+function getSSLConfig(): KafkaConfig["ssl"] {
+    if (KAFKA_TLS !== "true") {
+        return undefined;
+    }
+    if (!KAFKA_TLS_CA && !KAFKA_TLS_CERT) {
+        return true;
+    }
+    return {
+        ca: KAFKA_TLS_CA ? [KAFKA_TLS_CA] : undefined,
+        cert: KAFKA_TLS_CERT,
+        key: KAFKA_TLS_KEY,
+    };
+}
+
+// SASL credentials; the mechanism uses the Knative saslType spelling
+function getSASLConfig(): SASLOptions | undefined {
+    if (
+        !KAFKA_SASL_MECHANISM ||
+        !KAFKA_SASL_USERNAME ||
+        !KAFKA_SASL_PASSWORD
+    ) {
+        return undefined;
+    }
+    const mechanism = KAFKA_SASL_MECHANISM.toLowerCase();
+    if (
+        mechanism === "plain" ||
+        mechanism === "scram-sha-256" ||
+        mechanism === "scram-sha-512"
+    ) {
+        return {
+            mechanism,
+            username: KAFKA_SASL_USERNAME,
+            password: KAFKA_SASL_PASSWORD,
+        };
+    }
+    throw new Error(
+        `[Kafka Queue] Unsupported SASL mechanism: ${KAFKA_SASL_MECHANISM}`,
+    );
+}
 
 // Kafka client configuration
 const kafka = new Kafka({
     clientId: KAFKA_CLIENT_ID ?? "kn-next-revalidation",
     brokers: (KAFKA_BROKER_URL ?? "localhost:9092").split(","),
+    ssl: getSSLConfig(),
+    sasl: getSASLConfig(),
     retry: {
         initialRetryTime: 100,
         retries: 3,
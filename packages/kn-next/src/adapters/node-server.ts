@@ -16,6 +16,37 @@ import {
 
 const PORT = Number.parseInt(process.env.PORT || "8080", 10);
 
+/**
+ * Parses KN_DOMAINS, the comma-separated hosts the operator maps to the app
+ * with Knative DomainMappings (spec.domains).
+ */
+export function parseDomains(value: string | undefined): Set<string> {
+    return new Set(
+        (value ?? "")
+            .split(",")
+            .map((host) => host.trim().toLowerCase())
+            .filter((host) => host !== ""),
+    );
+}
+
+/**
+ * Returns the host a DomainMapping request was addressed to, taken from
+ * K-Original-Host. Any client can send the header, so it is only trusted
+ * when it names one of the app's own domains.
+ */
+export function originalHost(
+    header: string | string[] | undefined,
+    domains: Set<string>,
+): string | undefined {
+    if (typeof header !== "string") {
+        return undefined;
+    }
+    const host = header.trim().toLowerCase();
+    return domains.has(host.replace(/:\d+$/, "")) ? host : undefined;
+}
+
+const DOMAINS = parseDomains(process.env.KN_DOMAINS);
+
 /**
  * Node.js HTTP server wrapper handler for Knative.
  * Runs the OpenNext handler as a standalone HTTP server instead of Lambda.
@@ -38,6 +69,17 @@ const wrapperHandler = async (
                 return;
             }
 
+            // Knative DomainMappings rewrite Host to the Service's hostname
+            // and keep the requested one in K-Original-Host. Restore it so
+            // Next.js i18n domain routing sees the custom domain.
+            const mappedHost = originalHost(
+                req.headers["k-original-host"],
+                DOMAINS,
+            );
+            if (mappedHost) {
+                req.headers.host = mappedHost;
+            }
+
             // Convert Node.js request to internal event format
             const internalEvent = await converter.convertFrom(req);
 
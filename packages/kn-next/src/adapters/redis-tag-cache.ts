@@ -6,13 +6,16 @@ import type {
 import Redis from "ioredis";
 import { logCacheEvent } from "./cache-events";
 
-const { REDIS_URL, REDIS_KEY_PREFIX } = process.env;
+const { REDIS_URL, REDIS_KEY_PREFIX, REDIS_TLS } = process.env;
 
-// Redis client with automatic reconnection
+// Redis client with automatic reconnection. REDIS_TLS turns on TLS for a
+// redis:// URL too, e.g. one read from a Secret; the CA comes from
+// NODE_EXTRA_CA_CERTS.
 const redis = new Redis(REDIS_URL ?? "redis://localhost:6379", {
     lazyConnect: true,
     maxRetriesPerRequest: 3,
     retryStrategy: (times) => Math.min(times * 50, 2000),
+    ...(REDIS_TLS === "true" ? { tls: {} } : {}),
 });
 
 // Ensure connection on first use (with race condition protection)
@@ -446,7 +446,7 @@ async function deploy() {
     if (!options.skipBuild) {
         // Inject ASSET_PREFIX so next.config.ts picks up the CDN URL at build time
         // Set via process.env so turbo inherits it (shell inline vars get stripped by turbo)
-        const assetPrefix = getAssetPrefix(config.storage);
+        const assetPrefix = getAssetPrefix(config);
         process.env.ASSET_PREFIX = assetPrefix;
         console.info(`📦 Building Next.js (assetPrefix: ${assetPrefix})...`);
         await $`npm run build`.quiet();
@@ -6,7 +6,7 @@ export interface StorageConfig {
     bucket: string;
     region?: string;
     endpoint?: string; // For MinIO/S3-compatible
-    publicUrl: string; // Public CDN URL where assets are served from (e.g. https://storage.googleapis.com/my-bucket)
+    publicUrl: string; // Public CDN URL of the bucket (e.g. https://storage.googleapis.com/my-bucket); assets are served from {publicUrl}/{name}/
     accessKey?: string; // Optional, use IAM when possible
     secretKey?: string;
 }
@@ -32,7 +32,7 @@ export interface DynamoDBCacheConfig {
 export type CacheConfig = RedisCacheConfig | DynamoDBCacheConfig;
 
 // Queue providers for ISR revalidation
-export type QueueProvider = "kafka" | "none";
+export type QueueProvider = "kafka" | "broker" | "none";
 
 export interface KafkaQueueConfig {
     provider: "kafka";
@@ -41,11 +41,16 @@ export interface KafkaQueueConfig {
     clientId?: string;
 }
 
+export interface BrokerQueueConfig {
+    provider: "broker";
+    broker?: string; // Knative Eventing Broker name, defaults to 'default'
+}
+
 export interface NoQueueConfig {
     provider: "none";
 }
 
-export type QueueConfig = KafkaQueueConfig | NoQueueConfig;
+export type QueueConfig = KafkaQueueConfig | BrokerQueueConfig | NoQueueConfig;
 
 // Infrastructure services (deployed as Knative services)
 export interface PostgresConfig {
@@ -22,6 +22,7 @@ const ADAPTER_MAP = {
     },
     queue: {
         kafka: "@kn-next/config/adapters/kafka-queue",
+        broker: "@kn-next/config/adapters/broker-queue",
         sqs: "@opennextjs/aws/overrides/queue/sqs",
         direct: "@opennextjs/aws/overrides/queue/direct",
     },
@@ -43,9 +44,14 @@ function getQueueAdapter(
 ): string {
     // First check if config.queue is explicitly set
     if (config.queue) {
-        return config.queue.provider === "kafka"
-            ? ADAPTER_MAP.queue.kafka
-            : ADAPTER_MAP.queue.direct;
+        switch (config.queue.provider) {
+            case "kafka":
+                return ADAPTER_MAP.queue.kafka;
+            case "broker":
+                return ADAPTER_MAP.queue.broker;
+            default:
+                return ADAPTER_MAP.queue.direct;
+        }
     }
     // Fall back to enableKafkaQueue flag or default to Kafka for Knative
     return enableKafkaQueue !== false
@@ -72,7 +78,11 @@ export function generateOpenNextConfig(
     const cacheAdapter = ADAPTER_MAP.cache[config.cache?.provider ?? "redis"];
     const queueAdapter = getQueueAdapter(config, enableKafkaQueue);
     const queueProvider =
-        queueAdapter === ADAPTER_MAP.queue.kafka ? "kafka" : "direct";
+        queueAdapter === ADAPTER_MAP.queue.kafka
+            ? "kafka"
+            : queueAdapter === ADAPTER_MAP.queue.broker
+              ? "broker"
+              : "direct";
 
     const configContent = `// AUTO-GENERATED by kn-next build - DO NOT EDIT
 // Source: kn-next.config.ts
@@ -136,13 +146,15 @@ const storageEnvVarGenerators: Record<string, EnvVarGenerator<any>> = {
         GCS_BUCKET_NAME: storage.bucket,
         GCS_BUCKET_KEY_PREFIX: appName,
     }),
-    s3: (storage) => ({
+    s3: (storage, appName) => ({
         CACHE_BUCKET_NAME: storage.bucket,
+        CACHE_BUCKET_KEY_PREFIX: appName,
         CACHE_BUCKET_REGION: storage.region ?? "us-east-1",
         ...(storage.endpoint ? { S3_ENDPOINT: storage.endpoint } : {}),
     }),
-    minio: (storage) => ({
+    minio: (storage, appName) => ({
         CACHE_BUCKET_NAME: storage.bucket,
+        CACHE_BUCKET_KEY_PREFIX: appName,
         CACHE_BUCKET_REGION: storage.region ?? "us-east-1",
         ...(storage.endpoint ? { S3_ENDPOINT: storage.endpoint } : {}),
     }),
@@ -1,17 +1,26 @@
 import { readdirSync } from "node:fs";
 import { join, relative } from "node:path";
 import { $ } from "bun";
-import type { KnativeNextConfig, StorageConfig } from "../config";
+import type { KnativeNextConfig } from "../config";
 
 /**
  * Returns the asset prefix URL from the storage configuration.
  * This is cloud-agnostic — the user declares `publicUrl` in their config.
  *
  * Used as Next.js `assetPrefix` so browsers load static assets
- * (_next/static/*) from the user's object storage bucket.
+ * (_next/static/*) from the app's folder in the user's object storage bucket.
  */
-export function getAssetPrefix(storage: StorageConfig): string {
-    return storage.publicUrl;
+export function getAssetPrefix(config: KnativeNextConfig): string {
+    return `${config.storage.publicUrl.replace(/\/+$/, "")}/${config.name}`;
+}
+
+/**
+ * Returns the bucket path assets are uploaded to. Assets live under the app's
+ * name, next to its ISR cache entries, so apps sharing a bucket stay apart and
+ * the operator's Delete policy purges them with the rest of the app's data.
+ */
+export function getAssetPath(config: KnativeNextConfig): string {
+    return `${config.storage.bucket}/${config.name}`;
 }
 
 /**
@@ -39,28 +48,27 @@ function collectFiles(dir: string, baseDir: string): string[] {
  */
 export async function uploadAssets(config: KnativeNextConfig): Promise<void> {
     const assetsDir = join(process.cwd(), ".open-next", "assets");
+    const assetPath = getAssetPath(config);
 
-    console.info(
-        `   Syncing to ${config.storage.provider}://${config.storage.bucket}`,
-    );
+    console.info(`   Syncing to ${config.storage.provider}://${assetPath}`);
 
     switch (config.storage.provider) {
         case "gcs": {
             // Upload with cache-control headers for immutable _next/static assets
-            await $`gsutil -m -h "Cache-Control:public, max-age=31536000, immutable" cp -r ${assetsDir}/* gs://${config.storage.bucket}/`.quiet();
+            await $`gsutil -m -h "Cache-Control:public, max-age=31536000, immutable" cp -r ${assetsDir}/* gs://${assetPath}/`.quiet();
             // Ensure bucket has public read access for browser fetches
             await $`gsutil iam ch allUsers:objectViewer gs://${config.storage.bucket}`.quiet();
 
             // Post-upload verification: ensure all local files exist in GCS
             const localFiles = collectFiles(assetsDir, assetsDir);
             const gcsListResult =
-                await $`gsutil ls -r "gs://${config.storage.bucket}/"`.text();
+                await $`gsutil ls -r "gs://${assetPath}/"`.text();
             const gcsFiles = new Set(
                 gcsListResult
                     .split("\n")
                     .filter((line) => line.startsWith("gs://"))
                     .map((line) =>
-                        line.replace(`gs://${config.storage.bucket}/`, ""),
+                        line.replace(`gs://${assetPath}/`, ""),
                     ),
             );
 
@@ -72,7 +80,7 @@ export async function uploadAssets(config: KnativeNextConfig): Promise<void> {
                 );
                 for (const file of missing) {
                     const localPath = join(assetsDir, file);
-                    const gcsPath = `gs://${config.storage.bucket}/${file}`;
+                    const gcsPath = `gs://${assetPath}/${file}`;
                     await $`gsutil -h "Cache-Control:public, max-age=31536000, immutable" cp ${localPath} ${gcsPath}`.quiet();
                 }
                 console.info(
@@ -82,14 +90,14 @@ export async function uploadAssets(config: KnativeNextConfig): Promise<void> {
             break;
         }
         case "s3":
-            await $`aws s3 sync ${assetsDir} s3://${config.storage.bucket} --cache-control "public, max-age=31536000, immutable"`.quiet();
+            await $`aws s3 sync ${assetsDir} s3://${assetPath} --cache-control "public, max-age=31536000, immutable"`.quiet();
             break;
         case "minio":
             // MinIO uses S3-compatible CLI
-            await $`mc cp --recursive ${assetsDir}/* minio/${config.storage.bucket}/`.quiet();
+            await $`mc cp --recursive ${assetsDir}/* minio/${assetPath}/`.quiet();
             break;
         case "azure":
-            await $`az storage blob upload-batch -d ${config.storage.bucket} -s ${assetsDir}`.quiet();
+            await $`az storage blob upload-batch -d ${config.storage.bucket} --destination-path ${config.name} -s ${assetsDir}`.quiet();
             break;
         default:
             throw new Error(